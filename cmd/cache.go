@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// newCacheCmd creates the command managing the examples cache
+func newCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of downloaded examples",
+		Args:  cobra.NoArgs,
+		RunE:  subcommandNameError,
+	}
+
+	cacheCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the cached examples",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := example.NewCache()
+			if err != nil {
+				return fmt.Errorf("failed to find the cache directory: %w", err)
+			}
+			return listCache(cmd.OutOrStdout(), cache)
+		},
+	})
+
+	cacheCmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached examples",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := example.NewCache()
+			if err != nil {
+				return fmt.Errorf("failed to find the cache directory: %w", err)
+			}
+			freed, err := cache.Clear()
+			if err != nil {
+				return fmt.Errorf("failed to clear the cache: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s Removed the cached examples, freeing %s.\n", util.Success("Done!"), util.FormatSize(freed))
+			return nil
+		},
+	})
+
+	return cacheCmd
+}
+
+// listCache prints the cached examples with their size and age
+func listCache(w io.Writer, cache *example.Cache) error {
+	entries, err := cache.Entries()
+	if err != nil {
+		return fmt.Errorf("failed to read the cache: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "No cached examples in %s.\n", cache.Dir)
+		return nil
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+		fmt.Fprintf(w, "%s %s, fetched %s\n",
+			util.Cyan(entry.RepoInfo().String()),
+			util.Info("("+util.FormatSize(entry.Size)+")"),
+			entry.FetchedAt.Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(w, "\n%d examples, %s in %s\n", len(entries), util.FormatSize(total), cache.Dir)
+	return nil
+}
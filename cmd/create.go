@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/install"
+	"github.com/yeasin2002/better-next-app/internal/prompt"
+	"github.com/yeasin2002/better-next-app/internal/template"
+	"github.com/yeasin2002/better-next-app/internal/util"
+	"github.com/yeasin2002/better-next-app/internal/validate"
+)
+
+const defaultProjectName = "my-app"
+
+// promptedFields are the options asked for when customizing settings
+var promptedFields = []string{
+	config.FieldTypeScript,
+	config.FieldLinter,
+	config.FieldReactCompiler,
+	config.FieldTailwind,
+	config.FieldSrcDir,
+	config.FieldImportAlias,
+}
+
+// runCreate resolves the configuration and scaffolds the project. With
+// --json the human output goes to stderr and a report is printed on
+// stdout, whether or not creation succeeds.
+func runCreate(cmd *cobra.Command, args []string) error {
+	opts := optionsFromFlags(cmd)
+	if !opts.json {
+		return create(cmd, args, cmd.OutOrStdout(), opts, newRunReport())
+	}
+
+	report := newRunReport()
+	err := create(cmd, args, cmd.ErrOrStderr(), opts, report)
+	report.setError(err)
+	if writeErr := report.write(cmd.OutOrStdout()); writeErr != nil && err == nil {
+		err = fmt.Errorf("failed to write the report: %w", writeErr)
+	}
+	return err
+}
+
+// create runs a single project creation, recording it in report
+func create(cmd *cobra.Command, args []string, out io.Writer, opts createOptions, report *runReport) error {
+	if reset, _ := cmd.Flags().GetBool("reset-preferences"); reset {
+		return resetPreferences(out)
+	}
+
+	flagCfg, explicit, err := configFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, sources, err := resolveConfig(cmd.Context(), args, flagCfg, explicit, opts)
+	if err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			fmt.Fprintln(out, "Exiting.")
+		}
+		return err
+	}
+	report.Config = cfg
+	report.Sources = sources
+
+	var source example.Source
+	if cfg.Example != "" {
+		if source, err = resolveExample(cmd.Context(), out, cfg); err != nil {
+			return err
+		}
+	}
+
+	if opts.dryRun {
+		project, err := runDryRun(out, cfg, source)
+		if err != nil {
+			return err
+		}
+		report.setDryRun(project)
+		return nil
+	}
+
+	if err := prepareDirectory(cfg); err != nil {
+		var dirErr *validate.DirectoryError
+		if errors.As(err, &dirErr) {
+			printConflicts(out, dirErr)
+		}
+		return err
+	}
+
+	fmt.Fprintf(out, "Creating a new Next.js app in %s.\n\n", util.Success(cfg.ProjectPath))
+
+	result, err := createProject(cmd.Context(), out, cfg, source)
+	report.setResult(cfg, result, err)
+	if err != nil {
+		return err
+	}
+
+	printSummary(out, cfg, result)
+	return nil
+}
+
+// resolveExample finds the example to download before anything is written
+func resolveExample(ctx context.Context, w io.Writer, cfg *config.Config) (example.Source, error) {
+	source, err := example.NewClient().Resolve(ctx, cfg.Example, cfg.ExamplePath)
+	if err == nil {
+		return source, nil
+	}
+
+	var notFound *example.NotFoundError
+	if errors.As(err, &notFound) && !example.IsLocal(cfg.Example) {
+		fmt.Fprintf(w, "Could not locate the example %s. It could be due to the following:\n", util.Error(cfg.Example))
+		fmt.Fprintln(w, "  1. The name, URL or path of the example might be misspelled. Start local paths with ./ or /.")
+		fmt.Fprintln(w, "  2. The repository might be private or the path might not exist on that branch.")
+		fmt.Fprintln(w, "  3. You might not be connected to the internet or you are behind a proxy.")
+	}
+	return nil, err
+}
+
+// resetPreferences clears the saved preferences
+func resetPreferences(w io.Writer) error {
+	if err := config.ClearPreferences(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to reset preferences: %w", err)
+	}
+	fmt.Fprintln(w, util.Success("The preferences have been reset successfully!"))
+	return nil
+}
+
+// resolveConfig builds the project configuration from the arguments,
+// flags and prompts
+func resolveConfig(ctx context.Context, args []string, flags *config.Config, explicit map[string]bool, opts createOptions) (*config.Config, map[string]string, error) {
+	var projectDir string
+	if len(args) > 0 {
+		projectDir = strings.TrimSpace(args[0])
+	}
+	dirSource := sourceArgument
+
+	if projectDir == "" && opts.nonInteractive {
+		return nil, nil, missingInputError(opts.nonInteractiveReason(), []expectedInput{
+			{"<directory>", "the project directory, e.g. `better-next-app my-app --yes`"},
+		})
+	}
+
+	if projectDir == "" {
+		name, err := prompt.AskProjectName(defaultProjectName)
+		if err != nil {
+			return nil, nil, err
+		}
+		projectDir = strings.TrimSpace(name)
+		dirSource = sourcePrompt
+	}
+
+	projectPath, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	projectName := filepath.Base(projectPath)
+	if err := validate.ValidateNpmName(projectName); err != nil {
+		return nil, nil, fmt.Errorf("could not create a project called %q because of npm naming restrictions: %w", projectName, err)
+	}
+
+	cfg, sources, err := askConfig(ctx, flags, explicit, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Explicit --use-* flags always win over detection
+	if !explicit[config.FieldPackageManager] {
+		detection := install.DetectPackageManager(filepath.Dir(projectPath))
+		cfg.PackageManager = detection.PackageManager
+		sources[config.FieldPackageManager] = sourceDetected + ":" + detection.Source
+	}
+
+	cfg.ProjectName = projectName
+	cfg.ProjectPath = projectPath
+	sources[config.FieldProjectPath] = dirSource
+	return cfg, sources, nil
+}
+
+// askConfig asks for the setup choice and the options it requires, and
+// reports where each value came from. Options set by flags are never
+// asked for.
+func askConfig(ctx context.Context, flags *config.Config, explicit map[string]bool, opts createOptions) (*config.Config, map[string]string, error) {
+	prefs, err := config.LoadPreferences()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load preferences: %w", err)
+	}
+
+	// Without prompts, defaults fill every option that was not set, or
+	// saved preferences when they were asked for
+	if opts.nonInteractive {
+		if !opts.reusePreferences {
+			prefs = nil
+		}
+		return config.MergeConfig(flags, prefs, explicit), configSources(explicit, prefs, nil), nil
+	}
+
+	if flags.Example != "" || (opts.reusePreferences && prefs != nil) {
+		return config.MergeConfig(flags, prefs, explicit), configSources(explicit, prefs, nil), nil
+	}
+
+	if !anyExplicit(explicit, promptedFields) {
+		choice, err := prompt.AskSetupChoice(prefs != nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch choice {
+		case prompt.SetupRecommended:
+			return config.MergeConfig(flags, nil, explicit), configSources(explicit, nil, nil), nil
+		case prompt.SetupReuse:
+			return config.MergeConfig(flags, prefs, explicit), configSources(explicit, prefs, nil), nil
+		case prompt.SetupExample:
+			name, err := askExample(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			cfg := config.MergeConfig(flags, nil, explicit)
+			cfg.Example = name
+			sources := configSources(explicit, nil, []string{config.FieldExample})
+			return cfg, sources, nil
+		}
+	}
+
+	cfg, err := askCustomConfig(config.MergeConfig(flags, prefs, explicit), explicit)
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := configSources(explicit, prefs, promptedFields)
+	if opts.dryRun {
+		return cfg, sources, nil
+	}
+	if err := config.SavePreferences(preferencesFromConfig(cfg)); err != nil {
+		return nil, nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return cfg, sources, nil
+}
+
+// askExample lets the user pick one of the official examples
+func askExample(ctx context.Context) (string, error) {
+	index, err := example.NewClient().Index(ctx, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the official examples: %w", err)
+	}
+
+	name, err := prompt.AskExample(func(term string) []prompt.ExampleOption {
+		var options []prompt.ExampleOption
+		for _, entry := range index.Search(term) {
+			options = append(options, prompt.ExampleOption{Name: entry.Name, Description: entry.Description})
+		}
+		return options
+	})
+	if err != nil {
+		return "", err
+	}
+	// An empty name would silently fall back to the default template
+	if name == "" {
+		return "", fmt.Errorf("no example was selected")
+	}
+	return name, nil
+}
+
+// anyExplicit reports whether any of fields was set by a flag
+func anyExplicit(explicit map[string]bool, fields []string) bool {
+	for _, field := range fields {
+		if explicit[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// askCustomConfig prompts for every customizable option not set by a flag
+func askCustomConfig(cfg *config.Config, explicit map[string]bool) (*config.Config, error) {
+	var err error
+
+	if !explicit[config.FieldTypeScript] {
+		if cfg.TypeScript, err = prompt.AskTypeScript(); err != nil {
+			return nil, err
+		}
+	}
+	if !explicit[config.FieldLinter] {
+		if cfg.Linter, err = prompt.AskLinter(); err != nil {
+			return nil, err
+		}
+	}
+	if !explicit[config.FieldReactCompiler] {
+		if cfg.ReactCompiler, err = prompt.AskReactCompiler(); err != nil {
+			return nil, err
+		}
+	}
+	if !explicit[config.FieldTailwind] {
+		if cfg.Tailwind, err = prompt.AskTailwind(); err != nil {
+			return nil, err
+		}
+	}
+	if !explicit[config.FieldSrcDir] {
+		if cfg.SrcDir, err = prompt.AskSrcDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !explicit[config.FieldImportAlias] {
+		customize, err := prompt.AskCustomizeImportAlias()
+		if err != nil {
+			return nil, err
+		}
+		cfg.ImportAlias = "@/*"
+		if customize {
+			if cfg.ImportAlias, err = prompt.AskImportAlias(template.PackageNames()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// preferencesFromConfig converts a configuration into saved preferences
+func preferencesFromConfig(cfg *config.Config) *config.Preferences {
+	return &config.Preferences{
+		TypeScript:     cfg.TypeScript,
+		Linter:         cfg.Linter,
+		Tailwind:       cfg.Tailwind,
+		AppRouter:      cfg.AppRouter,
+		SrcDir:         cfg.SrcDir,
+		ImportAlias:    cfg.ImportAlias,
+		CustomizeAlias: cfg.ImportAlias != "@/*",
+		EmptyTemplate:  cfg.EmptyTemplate,
+		DisableGit:     cfg.SkipGit,
+		ReactCompiler:  cfg.ReactCompiler,
+	}
+}
+
+// prepareDirectory validates the project directory
+func prepareDirectory(cfg *config.Config) error {
+	if err := validate.ValidateDirectory(cfg.ProjectPath); err != nil {
+		return err
+	}
+
+	empty, conflicting, err := validate.IsFolderEmpty(cfg.ProjectPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	if !empty {
+		return &validate.DirectoryError{Path: cfg.ProjectPath, ConflictingFiles: conflicting}
+	}
+
+	return nil
+}
+
+// printConflicts lists files that prevent creating the project
+func printConflicts(w io.Writer, err *validate.DirectoryError) {
+	fmt.Fprintf(w, "The directory %s contains files that could conflict:\n\n", util.Success(filepath.Base(err.Path)))
+	for _, file := range err.ConflictingFiles {
+		fmt.Fprintf(w, "  %s\n", file)
+	}
+	fmt.Fprintln(w, "\nEither try using a new directory name, or remove the files listed above.")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/install"
+)
+
+func TestResolveConfigPackageManager(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("npm_config_user_agent", "pnpm/9.1.0 npm/? node/v20.11.0 linux x64")
+
+	tests := []struct {
+		name       string
+		flag       string // The --use-* package manager, if any
+		want       string
+		wantSource string
+	}{
+		{"detected", "", "pnpm", sourceDetected + ":" + install.SourceUserAgent},
+		{"explicit flag skips detection", "bun", "bun", sourceFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := &config.Config{}
+			explicit := map[string]bool{}
+			if tt.flag != "" {
+				flags.PackageManager = tt.flag
+				explicit[config.FieldPackageManager] = true
+			}
+
+			args := []string{filepath.Join(t.TempDir(), "my-app")}
+			cfg, sources, err := resolveConfig(context.Background(), args, flags, explicit, createOptions{nonInteractive: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.PackageManager != tt.want || sources[config.FieldPackageManager] != tt.wantSource {
+				t.Errorf("package manager = %q from %q, want %q from %q",
+					cfg.PackageManager, sources[config.FieldPackageManager], tt.want, tt.wantSource)
+			}
+		})
+	}
+}
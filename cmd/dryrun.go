@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/template"
+	"github.com/yeasin2002/better-next-app/internal/util"
+	"github.com/yeasin2002/better-next-app/internal/validate"
+)
+
+// runDryRun renders the project in memory, prints what would be created
+// and returns the rendered project. Examples are not extracted, so no
+// project is returned for them.
+func runDryRun(w io.Writer, cfg *config.Config, source example.Source) (*template.Project, error) {
+	// Only read the directory, a dry run never writes to disk
+	empty, conflicting, err := validate.IsFolderEmpty(cfg.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	if !empty {
+		dirErr := &validate.DirectoryError{Path: cfg.ProjectPath, ConflictingFiles: conflicting}
+		printConflicts(w, dirErr)
+		return nil, dirErr
+	}
+
+	var project *template.Project
+	if source != nil {
+		fmt.Fprintf(w, "%s Would create a new Next.js app in %s from %s.\n\n", util.Warning("Dry run:"), util.Success(cfg.ProjectPath), util.Cyan(source.String()))
+	} else {
+		project, err = template.Render(templatesFS, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(w, "%s Would create a new Next.js app in %s.\n\n", util.Warning("Dry run:"), util.Success(cfg.ProjectPath))
+		printProjectTree(w, cfg.ProjectName, project.Files())
+
+		pkg := project.Get("package.json")
+		if pkg == nil {
+			return nil, errors.New("rendered project has no package.json")
+		}
+		fmt.Fprintf(w, "\n%s\n%s\n", util.Bold("package.json"), pkg.Data)
+	}
+
+	if !cfg.SkipInstall {
+		fmt.Fprintf(w, "Would install dependencies with %s.\n", util.Cyan(cfg.PackageManager))
+	}
+	if !cfg.SkipGit {
+		fmt.Fprintln(w, "Would initialize a git repository.")
+	}
+	fmt.Fprintln(w, "Nothing was written to disk.")
+
+	return project, nil
+}
+
+// printProjectTree prints files as a directory tree with their sizes.
+// files must be sorted by path.
+func printProjectTree(w io.Writer, root string, files []*template.File) {
+	fmt.Fprintf(w, "%s/\n", util.Bold(root))
+
+	var total int64
+	var printed []string
+	for _, file := range files {
+		parts := strings.Split(file.Path, "/")
+
+		// Print directories not shared with the previous file
+		for depth := 0; depth < len(parts)-1; depth++ {
+			if depth < len(printed) && printed[depth] == parts[depth] {
+				continue
+			}
+			printed = append(printed[:depth], parts[depth])
+			fmt.Fprintf(w, "%s%s/\n", strings.Repeat("  ", depth+1), util.Blue(parts[depth]))
+		}
+		printed = printed[:len(parts)-1]
+
+		size := int64(len(file.Data))
+		total += size
+		fmt.Fprintf(w, "%s%s %s\n", strings.Repeat("  ", len(parts)), parts[len(parts)-1], util.Info("("+util.FormatSize(size)+")"))
+	}
+
+	fmt.Fprintf(w, "\n%d files, %s\n", len(files), util.FormatSize(total))
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// ansiEscape matches the colour codes of styled output
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestDryRunGolden(t *testing.T) {
+	templatesFS = os.DirFS("..")
+
+	tests := []struct {
+		name   string
+		config func(*config.Config)
+	}{
+		{"default", func(*config.Config) {}},
+		{"javascript-src-dir", func(cfg *config.Config) {
+			cfg.TypeScript = false
+			cfg.SrcDir = true
+			cfg.Tailwind = false
+			cfg.Linter = "biome"
+			cfg.SkipGit = true
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := config.DefaultConfig()
+			cfg.ProjectName = "my-app"
+			cfg.ProjectPath = filepath.Join(dir, "my-app")
+			tt.config(cfg)
+
+			var out bytes.Buffer
+			if _, err := runDryRun(&out, cfg, nil); err != nil {
+				t.Fatal(err)
+			}
+			got := ansiEscape.ReplaceAllString(out.String(), "")
+			got = strings.ReplaceAll(got, dir, "<dir>")
+
+			golden := filepath.Join("testdata", "dryrun-"+tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v, run go test ./cmd -update to create it", err)
+			}
+			if got != string(want) {
+				t.Errorf("dry run output differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestDryRunLeavesNoFiles(t *testing.T) {
+	templatesFS = os.DirFS("..")
+
+	cfg := config.DefaultConfig()
+	cfg.ProjectName = "my-app"
+	cfg.ProjectPath = filepath.Join(t.TempDir(), "my-app")
+
+	if _, err := runDryRun(&bytes.Buffer{}, cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg.ProjectPath); !os.IsNotExist(err) {
+		t.Errorf("the dry run created %s", cfg.ProjectPath)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Exit codes returned by the CLI
+const (
+	ExitFailure      = 1 // Project creation failed
+	ExitMissingInput = 2 // A required value is missing and prompts are disabled
+)
+
+// ExitError is an error with a specific process exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// expectedInput describes a required value and how to provide it
+type expectedInput struct {
+	usage       string
+	description string
+}
+
+// missingInputError reports required values that could not be prompted for
+func missingInputError(reason string, missing []expectedInput) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cannot prompt for input in non-interactive mode (%s), missing:", reason)
+	for _, input := range missing {
+		fmt.Fprintf(&b, "\n  %-14s %s", input.usage, input.description)
+	}
+	return &ExitError{Code: ExitMissingInput, Err: fmt.Errorf("%s", b.String())}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// newExamplesCmd creates the command browsing the official examples
+func newExamplesCmd() *cobra.Command {
+	examplesCmd := &cobra.Command{
+		Use:   "examples",
+		Short: "Browse the official Next.js examples usable with --example",
+		Args:  cobra.NoArgs,
+		RunE:  subcommandNameError,
+	}
+	examplesCmd.PersistentFlags().Bool("refresh", false, "Fetch the list of examples again instead of using the cached one")
+
+	examplesCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the official examples",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			index, err := loadExamplesIndex(cmd)
+			if err != nil {
+				return err
+			}
+			printExamples(cmd.OutOrStdout(), index, index.Examples)
+			return nil
+		},
+	})
+
+	examplesCmd.AddCommand(&cobra.Command{
+		Use:   "search <term>",
+		Short: "Search the official examples by name and description",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			index, err := loadExamplesIndex(cmd)
+			if err != nil {
+				return err
+			}
+			term := strings.Join(args, " ")
+			matches := index.Search(term)
+			if len(matches) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No examples match %q.\n", term)
+				return nil
+			}
+			printExamples(cmd.OutOrStdout(), index, matches)
+			return nil
+		},
+	})
+
+	return examplesCmd
+}
+
+// loadExamplesIndex fetches the examples index, or reads it from the cache
+func loadExamplesIndex(cmd *cobra.Command) (*example.Index, error) {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	index, err := example.NewClient().Index(cmd.Context(), refresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the official examples: %w", err)
+	}
+	return index, nil
+}
+
+// printExamples prints the examples with the first line of their README
+func printExamples(w io.Writer, index *example.Index, entries []example.IndexEntry) {
+	width := 0
+	for _, entry := range entries {
+		width = max(width, len(entry.Name))
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s%s  %s\n", util.Cyan(entry.Name), strings.Repeat(" ", width-len(entry.Name)), entry.Description)
+	}
+
+	fmt.Fprintf(w, "\n%d examples. Use one with %s.\n", len(entries), util.Cyan("better-next-app my-app --example <name>"))
+	if index.Stale {
+		fmt.Fprintf(w, "%s GitHub could not be reached, this list was fetched %s and may be out of date.\n",
+			util.Warning("Warning:"), index.FetchedAt.Local().Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/prompt"
+	"github.com/yeasin2002/better-next-app/internal/template"
+	"github.com/yeasin2002/better-next-app/internal/validate"
+)
+
+// exclusiveFlags lists groups of flags that cannot be combined
+var exclusiveFlags = [][]string{
+	{"typescript", "javascript"},
+	{"tailwind", "no-tailwind"},
+	{"src-dir", "no-src-dir"},
+	{"eslint", "biome", "no-lint"},
+	{"react-compiler", "no-react-compiler"},
+	{"turbo", "webpack", "rspack"},
+	{"use-npm", "use-pnpm", "use-yarn", "use-bun"},
+}
+
+// createOptions are the flags that control how a project is created
+// rather than what it contains
+type createOptions struct {
+	yes              bool
+	nonInteractive   bool // Never prompt, set by --yes, --non-interactive, --json or CI
+	ci               bool
+	reusePreferences bool
+	dryRun           bool
+	json             bool
+}
+
+// registerFlags adds the create-next-app compatible flags to cmd
+func registerFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	// Project Configuration
+	flags.Bool("typescript", false, "Initialize as a TypeScript project (default)")
+	flags.Bool("javascript", false, "Initialize as a JavaScript project")
+	flags.Bool("tailwind", false, "Initialize with Tailwind CSS config (default)")
+	flags.Bool("no-tailwind", false, "Initialize without Tailwind CSS config")
+	flags.Bool("src-dir", false, "Initialize inside a `src/` directory")
+	flags.Bool("no-src-dir", false, "Initialize without a `src/` directory")
+	flags.String("import-alias", "", "Specify import alias to use (default \"@/*\")")
+	flags.Bool("empty", false, "Initialize an empty project")
+	flags.Bool("api", false, "Initialize a headless API using the App Router")
+
+	// Linting & Tools
+	flags.Bool("eslint", false, "Initialize with ESLint config")
+	flags.Bool("biome", false, "Initialize with Biome config")
+	flags.Bool("no-lint", false, "Initialize without a linter")
+	flags.Bool("react-compiler", false, "Initialize with React Compiler enabled")
+	flags.Bool("no-react-compiler", false, "Initialize without React Compiler")
+
+	// Bundler Options
+	flags.Bool("turbo", false, "Use Turbopack (default)")
+	flags.Bool("webpack", false, "Use Webpack")
+	flags.Bool("rspack", false, "Use Rspack")
+
+	// Package Manager
+	flags.Bool("use-npm", false, "Bootstrap the application using npm")
+	flags.Bool("use-pnpm", false, "Bootstrap the application using pnpm")
+	flags.Bool("use-yarn", false, "Bootstrap the application using Yarn")
+	flags.Bool("use-bun", false, "Bootstrap the application using Bun")
+	flags.Bool("skip-install", false, "Skip installing packages")
+
+	// Git Options
+	flags.Bool("skip-git", false, "Skip initializing a git repository")
+
+	// Example Mode
+	flags.StringP("example", "e", "", "An example to bootstrap the app with: a name from the official Next.js repo, a GitHub URL, or a local directory or archive")
+	flags.String("example-path", "", "Path within the repository, directory or archive when using --example (for monorepos)")
+
+	// Automation
+	flags.BoolP("yes", "y", false, "Skip all prompts and use defaults for unprovided options")
+	flags.Bool("non-interactive", false, "Never prompt, fail when a required value is missing (default in CI)")
+	flags.Bool("reuse-preferences", false, "Use saved preferences instead of defaults for unprovided options")
+	flags.Bool("reset-preferences", false, "Reset the saved preferences")
+	flags.Bool("dry-run", false, "Preview the generated project without writing, installing or initializing git")
+	flags.Bool("json", false, "Print a JSON report on stdout and all other output on stderr, implies --non-interactive")
+}
+
+// nonInteractiveReason names what disabled prompts
+func (o createOptions) nonInteractiveReason() string {
+	switch {
+	case o.yes:
+		return "--yes"
+	case o.json:
+		return "--json"
+	case o.ci:
+		return "CI detected"
+	default:
+		return "--non-interactive"
+	}
+}
+
+// optionsFromFlags reads the flags controlling the creation run
+func optionsFromFlags(cmd *cobra.Command) createOptions {
+	var opts createOptions
+	opts.yes, _ = cmd.Flags().GetBool("yes")
+	opts.nonInteractive, _ = cmd.Flags().GetBool("non-interactive")
+	opts.reusePreferences, _ = cmd.Flags().GetBool("reuse-preferences")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.json, _ = cmd.Flags().GetBool("json")
+
+	opts.ci = validate.IsCI()
+	opts.nonInteractive = opts.nonInteractive || opts.yes || opts.json || opts.ci
+	return opts
+}
+
+// checkExclusiveFlags rejects contradictory flag combinations
+func checkExclusiveFlags(cmd *cobra.Command) error {
+	for _, group := range exclusiveFlags {
+		var set []string
+		for _, name := range group {
+			if cmd.Flags().Changed(name) {
+				set = append(set, "--"+name)
+			}
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("%s cannot be used together, choose only one", strings.Join(set, " and "))
+		}
+	}
+	return nil
+}
+
+// configFromFlags reads the flags into a Config and reports which
+// fields were set explicitly
+func configFromFlags(cmd *cobra.Command) (*config.Config, map[string]bool, error) {
+	if err := checkExclusiveFlags(cmd); err != nil {
+		return nil, nil, err
+	}
+
+	flags := cmd.Flags()
+	cfg := config.New()
+	explicit := map[string]bool{}
+
+	// Boolean options with a positive and a negative flag
+	pairs := []struct {
+		field, on, off string
+		value          *bool
+	}{
+		{config.FieldTypeScript, "typescript", "javascript", &cfg.TypeScript},
+		{config.FieldTailwind, "tailwind", "no-tailwind", &cfg.Tailwind},
+		{config.FieldSrcDir, "src-dir", "no-src-dir", &cfg.SrcDir},
+		{config.FieldReactCompiler, "react-compiler", "no-react-compiler", &cfg.ReactCompiler},
+	}
+	for _, pair := range pairs {
+		if flags.Changed(pair.on) {
+			*pair.value, _ = flags.GetBool(pair.on)
+			explicit[pair.field] = true
+		}
+		if flags.Changed(pair.off) {
+			off, _ := flags.GetBool(pair.off)
+			*pair.value = !off
+			explicit[pair.field] = true
+		}
+	}
+
+	// Boolean options with a single flag
+	singles := []struct {
+		field, name string
+		value       *bool
+	}{
+		{config.FieldEmptyTemplate, "empty", &cfg.EmptyTemplate},
+		{config.FieldAPIOnly, "api", &cfg.APIOnly},
+		{config.FieldSkipInstall, "skip-install", &cfg.SkipInstall},
+		{config.FieldSkipGit, "skip-git", &cfg.SkipGit},
+	}
+	for _, single := range singles {
+		if flags.Changed(single.name) {
+			*single.value, _ = flags.GetBool(single.name)
+			explicit[single.field] = true
+		}
+	}
+
+	// Options selected by one flag out of a group
+	choices := []struct {
+		field   string
+		value   *string
+		options map[string]string
+	}{
+		{config.FieldLinter, &cfg.Linter, map[string]string{"eslint": "eslint", "biome": "biome", "no-lint": "none"}},
+		{config.FieldBundler, &cfg.Bundler, map[string]string{"turbo": "turbopack", "webpack": "webpack", "rspack": "rspack"}},
+		{config.FieldPackageManager, &cfg.PackageManager, map[string]string{"use-npm": "npm", "use-pnpm": "pnpm", "use-yarn": "yarn", "use-bun": "bun"}},
+	}
+	for _, choice := range choices {
+		for name, value := range choice.options {
+			if on, _ := flags.GetBool(name); on && flags.Changed(name) {
+				*choice.value = value
+				explicit[choice.field] = true
+			}
+		}
+	}
+
+	if flags.Changed("import-alias") {
+		alias, _ := flags.GetString("import-alias")
+		if err := prompt.ValidateImportAlias(alias, template.PackageNames()); err != nil {
+			return nil, nil, fmt.Errorf("invalid --import-alias %q: %w", alias, err)
+		}
+		if alias != "" {
+			cfg.ImportAlias = alias
+			explicit[config.FieldImportAlias] = true
+		}
+	}
+
+	if flags.Changed("example") {
+		cfg.Example, _ = flags.GetString("example")
+		cfg.Example = strings.TrimSpace(cfg.Example)
+		explicit[config.FieldExample] = cfg.Example != ""
+	}
+	if flags.Changed("example-path") {
+		cfg.ExamplePath, _ = flags.GetString("example-path")
+		explicit[config.FieldExamplePath] = true
+	}
+	if cfg.ExamplePath != "" && cfg.Example == "" {
+		return nil, nil, fmt.Errorf("--example-path can only be used together with --example")
+	}
+
+	return cfg, explicit, nil
+}
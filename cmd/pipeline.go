@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/install"
+	"github.com/yeasin2002/better-next-app/internal/template"
+	"github.com/yeasin2002/better-next-app/internal/util"
+	"github.com/yeasin2002/better-next-app/internal/validate"
+)
+
+// phase identifies a creation phase in reports
+type phase string
+
+// Creation phases, in the order they run
+const (
+	phaseExample  phase = "example"
+	phaseTemplate phase = "template"
+	phaseInstall  phase = "install"
+	phaseGit      phase = "git"
+)
+
+// describe names the phase as it is reported when it fails
+func (p phase) describe() string {
+	switch p {
+	case phaseExample:
+		return "extracting the example"
+	case phaseTemplate:
+		return "writing the template"
+	case phaseInstall:
+		return "installing dependencies"
+	case phaseGit:
+		return "initializing git"
+	}
+	return string(p)
+}
+
+// phaseError reports the creation phase that failed
+type phaseError struct {
+	phase phase
+	err   error
+}
+
+func (e *phaseError) Error() string {
+	return fmt.Sprintf("failed while %s: %v", e.phase.describe(), e.err)
+}
+
+func (e *phaseError) Unwrap() error {
+	return e.err
+}
+
+// phaseTiming is how long a phase ran
+type phaseTiming struct {
+	phase    phase
+	duration time.Duration
+}
+
+// creationResult records what the creation phases did
+type creationResult struct {
+	files          []string // Template or example files written, relative to the project
+	exampleCached  bool
+	exampleStale   bool // The cached example could not be revalidated
+	rejected       []example.Rejection
+	installed      bool
+	offline        bool
+	gitInitialized bool
+	gitSkipped     bool
+	gitNotice      string // Why git was skipped or failed
+	rolledBack     bool   // Everything created was removed after a failure
+	timings        []phaseTiming
+}
+
+// createProject runs the creation phases, starting from source when an
+// example was resolved. Everything they created is removed again when a
+// phase fails or the run is interrupted, keeping files that were in the
+// directory before. The result is returned even when a phase fails.
+func createProject(ctx context.Context, out io.Writer, cfg *config.Config, source example.Source) (*creationResult, error) {
+	result := &creationResult{}
+
+	journal, err := util.NewJournal(cfg.ProjectPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	err = runPhases(ctx, out, cfg, source, journal, result)
+	if err == nil {
+		return result, nil
+	}
+
+	fmt.Fprintln(out)
+	if rollbackErr := journal.Rollback(); rollbackErr != nil {
+		fmt.Fprintf(out, "%s could not remove everything created in %s: %v\n", util.Warning("Warning:"), cfg.ProjectPath, rollbackErr)
+	} else {
+		result.rolledBack = true
+		fmt.Fprintf(out, "Removed the files created in %s.\n", cfg.ProjectPath)
+	}
+	printRetryHint(out, cfg, err)
+
+	return result, err
+}
+
+// runPhases writes the example or the template, installs dependencies
+// and initializes git, timing each phase that runs
+func runPhases(ctx context.Context, out io.Writer, cfg *config.Config, source example.Source, journal *util.Journal, result *creationResult) error {
+	phases := []struct {
+		phase phase
+		skip  bool
+		run   func() error
+	}{
+		{phaseExample, source == nil, func() error { return extractExample(ctx, out, cfg, source, journal, result) }},
+		{phaseTemplate, source != nil, func() error { return writeTemplate(ctx, cfg, journal, result) }},
+		{phaseInstall, cfg.SkipInstall, func() error { return installDependencies(ctx, out, cfg, journal, result) }},
+		{phaseGit, cfg.SkipGit, func() error { return initGit(ctx, out, cfg, journal, result) }},
+	}
+
+	for _, p := range phases {
+		if p.skip {
+			continue
+		}
+
+		start := time.Now()
+		err := p.run()
+		result.timings = append(result.timings, phaseTiming{p.phase, time.Since(start)})
+		if err != nil {
+			return &phaseError{p.phase, err}
+		}
+	}
+	return nil
+}
+
+// extractExample downloads or copies the example into the project
+// directory
+func extractExample(ctx context.Context, out io.Writer, cfg *config.Config, source example.Source, journal *util.Journal, result *creationResult) error {
+	if err := journal.MkdirAll(cfg.ProjectPath); err != nil {
+		return err
+	}
+
+	var opts example.DownloadOptions
+	if source.Remote() {
+		opts.Offline = !validate.CheckOnline(ctx, validate.ProbeOptions{Dir: cfg.ProjectPath})
+		fmt.Fprintf(out, "Downloading files from %s. This might take a moment.\n", util.Cyan(source.String()))
+	} else {
+		fmt.Fprintf(out, "Copying files from %s.\n", util.Cyan(source.String()))
+	}
+	downloaded, err := source.Extract(ctx, journal, opts)
+	if err != nil {
+		return err
+	}
+	if downloaded.Stale {
+		fmt.Fprintf(out, "%s Using the cached copy of the example, it may be out of date.\n", util.Warning("Warning:"))
+	}
+	for _, rejection := range downloaded.Rejected {
+		fmt.Fprintf(out, "%s Skipped %s: %s.\n", util.Warning("Warning:"), rejection.Path, rejection.Reason)
+	}
+
+	result.files = downloaded.Files
+	result.exampleCached = downloaded.FromCache
+	result.exampleStale = downloaded.Stale
+	result.rejected = downloaded.Rejected
+	return ctx.Err()
+}
+
+// writeTemplate renders the template into the project directory
+func writeTemplate(ctx context.Context, cfg *config.Config, journal *util.Journal, result *creationResult) error {
+	if err := journal.MkdirAll(cfg.ProjectPath); err != nil {
+		return err
+	}
+	project, err := template.Install(templatesFS, cfg, journal)
+	if err != nil {
+		return err
+	}
+	for _, file := range project.Files() {
+		result.files = append(result.files, file.Path)
+	}
+	return ctx.Err()
+}
+
+// installDependencies runs the package manager, from its local cache
+// when the registry is unreachable
+func installDependencies(ctx context.Context, out io.Writer, cfg *config.Config, journal *util.Journal, result *creationResult) error {
+	offline := !validate.CheckOnline(ctx, validate.ProbeOptions{Dir: cfg.ProjectPath})
+	result.offline = offline
+	if offline {
+		fmt.Fprintf(out, "%s You appear to be offline. Installing from the local %s cache.\n", util.Warning("Warning:"), cfg.PackageManager)
+	}
+
+	fmt.Fprintf(out, "Installing dependencies with %s...\n", util.Cyan(cfg.PackageManager))
+	err := install.InstallDependencies(ctx, cfg.ProjectPath, cfg.PackageManager, install.InstallOptions{
+		Offline: offline,
+		Out:     out,
+	})
+	if recordErr := journal.RecordNew(); err == nil {
+		err = recordErr
+	}
+	if err != nil {
+		return err
+	}
+	result.installed = true
+	return nil
+}
+
+// initGit creates the repository. Git is optional, so a skipped or
+// failed init doesn't fail the run.
+func initGit(ctx context.Context, out io.Writer, cfg *config.Config, journal *util.Journal, result *creationResult) error {
+	err := install.InitGit(ctx, cfg.ProjectPath)
+	if recordErr := journal.RecordNew(); recordErr != nil {
+		return recordErr
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	var skipped *install.GitSkippedError
+	switch {
+	case err == nil:
+		result.gitInitialized = true
+		fmt.Fprintln(out, "Initialized a git repository.")
+	case errors.As(err, &skipped):
+		result.gitSkipped = true
+		result.gitNotice = skipped.Reason
+		fmt.Fprintf(out, "%s %s.\n", util.Info("Notice:"), skipped.Error())
+	default:
+		result.gitNotice = err.Error()
+		fmt.Fprintf(out, "%s git initialization failed: %v\n", util.Warning("Warning:"), err)
+	}
+	return nil
+}
+
+// printRetryHint explains how to retry after a failed phase
+func printRetryHint(w io.Writer, cfg *config.Config, err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(w, "Project creation was interrupted. Run the same command again to start over.")
+		return
+	}
+
+	var phaseErr *phaseError
+	if !errors.As(err, &phaseErr) {
+		return
+	}
+
+	fmt.Fprintf(w, "%s Project creation failed while %s.\n", util.Error("Aborting."), phaseErr.phase.describe())
+
+	var installErr *install.InstallError
+	if errors.As(err, &installErr) {
+		fmt.Fprintln(w, installErr.Explanation())
+	}
+
+	switch phaseErr.phase {
+	case phaseInstall:
+		fmt.Fprintf(w, "Run the same command again to retry, or add %s and run %s in the project yourself.\n",
+			util.Cyan("--skip-install"), util.Cyan(cfg.PackageManager+" install"))
+	case phaseGit:
+		fmt.Fprintf(w, "Run the same command again to retry, or add %s to skip git.\n", util.Cyan("--skip-git"))
+	case phaseExample:
+		fmt.Fprintf(w, "Run the same command again to retry, or leave out %s to use the default template.\n", util.Cyan("--example"))
+	default:
+		fmt.Fprintln(w, "Run the same command again to retry.")
+	}
+}
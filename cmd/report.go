@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/example"
+	"github.com/yeasin2002/better-next-app/internal/install"
+	"github.com/yeasin2002/better-next-app/internal/template"
+)
+
+// reportSchemaVersion is increased whenever a field of the --json report
+// is renamed, removed or changes meaning. Adding fields keeps the version.
+const reportSchemaVersion = 1
+
+// Where a config value came from
+const (
+	sourceArgument    = "argument"
+	sourceFlag        = "flag"
+	sourcePrompt      = "prompt"
+	sourcePreferences = "preferences"
+	sourceDetected    = "detected" // Followed by the detection source, e.g. "detected:lockfile"
+	sourceDefault     = "default"
+)
+
+// Run statuses of the report, its install and its git step
+const (
+	statusSuccess     = "success"
+	statusDryRun      = "dry-run"
+	statusFailed      = "failed"
+	statusInstalled   = "installed"
+	statusInitialized = "initialized"
+	statusSkipped     = "skipped"
+	statusNotRun      = "not-run"
+)
+
+// runReport is the machine-readable result printed by --json
+type runReport struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Status        string            `json:"status"` // "success", "dry-run" or "failed"
+	ExitCode      int               `json:"exitCode"`
+	Error         string            `json:"error,omitempty"`
+	FailedPhase   string            `json:"failedPhase,omitempty"`
+	Config        *config.Config    `json:"config"`
+	Sources       map[string]string `json:"sources"`
+	Files         []string          `json:"files"`      // Template or example files relative to the project
+	RolledBack    bool              `json:"rolledBack"` // The files created by a failed run were removed
+	Example       *exampleReport    `json:"example,omitempty"`
+	Install       installReport     `json:"install"`
+	Git           gitReport         `json:"git"`
+	Phases        []phaseReport     `json:"phases"`
+	Warnings      []string          `json:"warnings"`
+}
+
+// exampleReport describes where the example came from
+type exampleReport struct {
+	Source   string              `json:"source"`
+	Cached   bool                `json:"cached"`
+	Stale    bool                `json:"stale"` // The cached copy could not be revalidated
+	Rejected []example.Rejection `json:"rejected"`
+}
+
+// installReport describes the dependency installation
+type installReport struct {
+	PackageManager string `json:"packageManager,omitempty"`
+	Status         string `json:"status"` // "installed", "skipped", "failed" or "not-run"
+	ExitCode       *int   `json:"exitCode"`
+	Offline        bool   `json:"offline"`
+}
+
+// gitReport describes the git initialization
+type gitReport struct {
+	Status string `json:"status"` // "initialized", "skipped", "failed" or "not-run"
+	Reason string `json:"reason,omitempty"`
+}
+
+// phaseReport is how long a creation phase ran
+type phaseReport struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// newRunReport starts a report for a run that has not done anything yet
+func newRunReport() *runReport {
+	return &runReport{
+		SchemaVersion: reportSchemaVersion,
+		Status:        statusSuccess,
+		Sources:       map[string]string{},
+		Files:         []string{},
+		Install:       installReport{Status: statusNotRun},
+		Git:           gitReport{Status: statusNotRun},
+		Phases:        []phaseReport{},
+		Warnings:      []string{},
+	}
+}
+
+// configSources reports where each config value came from, given the
+// fields set by flags, the preferences that were applied and the fields
+// that were prompted for
+func configSources(explicit map[string]bool, prefs *config.Preferences, prompted []string) map[string]string {
+	sources := map[string]string{}
+	for _, field := range config.Fields {
+		switch {
+		case explicit[field]:
+			sources[field] = sourceFlag
+		case slices.Contains(prompted, field):
+			sources[field] = sourcePrompt
+		case prefs != nil && slices.Contains(config.PreferenceFields, field):
+			sources[field] = sourcePreferences
+		default:
+			sources[field] = sourceDefault
+		}
+	}
+	return sources
+}
+
+// setDryRun records the files a dry run would have written, if known
+func (r *runReport) setDryRun(project *template.Project) {
+	r.Status = statusDryRun
+	if project == nil {
+		return
+	}
+	for _, file := range project.Files() {
+		r.Files = append(r.Files, file.Path)
+	}
+}
+
+// setResult records what the creation phases did. Files are only listed
+// when the run succeeded, since a failed run removes what it wrote.
+func (r *runReport) setResult(cfg *config.Config, result *creationResult, err error) {
+	if result.files != nil && err == nil {
+		r.Files = result.files
+	}
+	r.RolledBack = result.rolledBack
+	if cfg.Example != "" {
+		r.Example = &exampleReport{
+			Source:   cfg.Example,
+			Cached:   result.exampleCached,
+			Stale:    result.exampleStale,
+			Rejected: append([]example.Rejection{}, result.rejected...),
+		}
+	}
+	for _, timing := range result.timings {
+		r.Phases = append(r.Phases, phaseReport{Name: string(timing.phase), DurationMs: timing.duration.Milliseconds()})
+	}
+
+	var phaseErr *phaseError
+	failed := errors.As(err, &phaseErr)
+	if failed {
+		r.FailedPhase = string(phaseErr.phase)
+	}
+
+	r.Install.PackageManager = cfg.PackageManager
+	r.Install.Offline = result.offline
+	switch {
+	case cfg.SkipInstall:
+		r.Install.Status = statusSkipped
+	case result.installed:
+		r.Install.Status = statusInstalled
+		r.Install.ExitCode = new(int)
+	case failed && phaseErr.phase == phaseInstall:
+		r.Install.Status = statusFailed
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			r.Install.ExitCode = &code
+		}
+	}
+
+	switch {
+	case cfg.SkipGit:
+		r.Git = gitReport{Status: statusSkipped, Reason: "--skip-git was used"}
+	case result.gitInitialized:
+		r.Git.Status = statusInitialized
+	case result.gitSkipped:
+		r.Git = gitReport{Status: statusSkipped, Reason: result.gitNotice}
+	case result.gitNotice != "":
+		r.Git = gitReport{Status: statusFailed, Reason: result.gitNotice}
+	case failed && phaseErr.phase == phaseGit:
+		r.Git = gitReport{Status: statusFailed, Reason: phaseErr.err.Error()}
+	}
+
+	if err == nil {
+		r.Warnings = summaryWarnings(cfg, result, func(command string) string {
+			return "`" + command + "`"
+		})
+	}
+}
+
+// setError records how the run ended
+func (r *runReport) setError(err error) {
+	if err == nil {
+		return
+	}
+
+	r.Status = statusFailed
+	r.Error = err.Error()
+	r.ExitCode = ExitFailure
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		r.ExitCode = exitErr.Code
+	}
+
+	var installErr *install.InstallError
+	if errors.As(err, &installErr) {
+		r.Warnings = append(r.Warnings, installErr.Explanation())
+	}
+}
+
+// write prints the report as indented JSON
+func (r *runReport) write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+func TestReportOfFailedRun(t *testing.T) {
+	templatesFS = os.DirFS("..")
+
+	cfg := config.DefaultConfig()
+	cfg.ProjectName = "my-app"
+	cfg.ProjectPath = filepath.Join(t.TempDir(), "my-app")
+	cfg.SkipInstall = true
+	cfg.SkipGit = true
+
+	// The template is written, then the interrupted run is rolled back
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := createProject(ctx, &bytes.Buffer{}, cfg, nil)
+	if err == nil {
+		t.Fatal("createProject() succeeded with a cancelled context")
+	}
+	if len(result.files) == 0 {
+		t.Fatal("no template files were written before the rollback")
+	}
+	if _, statErr := os.Stat(cfg.ProjectPath); !os.IsNotExist(statErr) {
+		t.Fatalf("%s was not rolled back", cfg.ProjectPath)
+	}
+
+	report := newRunReport()
+	report.Config = cfg
+	report.setResult(cfg, result, err)
+	report.setError(err)
+
+	var out bytes.Buffer
+	if err := report.write(&out); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Status      string   `json:"status"`
+		FailedPhase string   `json:"failedPhase"`
+		Files       []string `json:"files"`
+		RolledBack  bool     `json:"rolledBack"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != statusFailed || got.FailedPhase != string(phaseTemplate) {
+		t.Errorf("status = %q, failedPhase = %q, want %q, %q", got.Status, got.FailedPhase, statusFailed, phaseTemplate)
+	}
+	if got.Files == nil || len(got.Files) != 0 {
+		t.Errorf("files = %v, want an empty list after the rollback", got.Files)
+	}
+	if !got.RolledBack {
+		t.Error("rolledBack = false after the rollback")
+	}
+}
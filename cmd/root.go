@@ -1,29 +1,42 @@
 package cmd
 
 import (
+	"context"
 	"embed"
 	"fmt"
+	"io/fs"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	templatesFS embed.FS
+	templatesFS fs.FS
 	rootCmd     *cobra.Command
 )
 
 func init() {
 	rootCmd = &cobra.Command{
-		Use:   "better-next-app",
-		Short: "A modern, high-performance CLI tool for scaffolding Next.js projects, written in Go",
-		Long:  ` A modern, high-performance CLI tool for scaffolding Next.js projects, written in Go. This is a complete rewrite of create-next-app that provides faster startup times, single binary distribution, and feature parity with the original TypeScript implementation. `,
-		// Run: func(cmd *cobra.Command, args []string) { },
+		Use:          "better-next-app [directory]",
+		Short:        "A modern, high-performance CLI tool for scaffolding Next.js projects, written in Go",
+		Long:         ` A modern, high-performance CLI tool for scaffolding Next.js projects, written in Go. This is a complete rewrite of create-next-app that provides faster startup times, single binary distribution, and feature parity with the original TypeScript implementation. `,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runCreate,
 	}
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	registerFlags(rootCmd)
+	rootCmd.AddCommand(newCacheCmd(), newExamplesCmd())
+	rootCmd.Long += "\n\nTo create a project called cache or examples, which are subcommands, pass it as a path, e.g. better-next-app ./cache."
 }
 
-func Execute(fs embed.FS) error {
+// subcommandNameError is run by a subcommand called without one of its
+// own subcommands. Its name takes the place of a project directory, so it
+// explains how to create a project with that name.
+func subcommandNameError(cmd *cobra.Command, args []string) error {
+	return fmt.Errorf("%q is a subcommand, see `%s --help`. To create a project called %s, run `better-next-app ./%s`",
+		cmd.Name(), cmd.CommandPath(), cmd.Name(), cmd.Name())
+}
+
+func Execute(ctx context.Context, fs embed.FS) error {
 	templatesFS = fs
-	fmt.Println(templatesFS)
-	return rootCmd.Execute()
+	return rootCmd.ExecuteContext(ctx)
 }
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSubcommandNameHint(t *testing.T) {
+	for _, name := range []string{"cache", "examples"} {
+		t.Run(name, func(t *testing.T) {
+			defer rootCmd.SetArgs(nil)
+			rootCmd.SetArgs([]string{name})
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+
+			err := rootCmd.ExecuteContext(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "better-next-app ./"+name) {
+				t.Errorf("err = %v, want a hint to use ./%s", err, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/install"
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// printSummary shows what was created and how to start working on it
+func printSummary(w io.Writer, cfg *config.Config, result *creationResult) {
+	pm := cfg.PackageManager
+
+	fmt.Fprintf(w, "\n%s Created %s at %s\n", util.Success("Success!"), util.Bold(cfg.ProjectName), cfg.ProjectPath)
+	if cfg.Example != "" {
+		fmt.Fprintf(w, "\n%s %s\n", util.Bold("Example:"), cfg.Example)
+	} else {
+		fmt.Fprintf(w, "\n%s %s\n", util.Bold("Features:"), strings.Join(describeFeatures(cfg), ", "))
+	}
+
+	fmt.Fprintln(w, "\nInside that directory, you can run several commands:")
+	for _, script := range []struct{ name, description string }{
+		{"dev", "Starts the development server."},
+		{"build", "Builds the app for production."},
+		{"start", "Runs the built app in production mode."},
+	} {
+		fmt.Fprintf(w, "\n  %s\n    %s\n", util.Cyan(install.RunScriptCommand(pm, script.name)), script.description)
+	}
+
+	fmt.Fprintln(w, "\nWe suggest that you begin by typing:")
+	fmt.Fprintln(w)
+	if dir := cdPath(cfg.ProjectPath); dir != "" {
+		fmt.Fprintf(w, "  %s %s\n", util.Cyan("cd"), dir)
+	}
+	if !result.installed {
+		fmt.Fprintf(w, "  %s\n", util.Cyan(pm+" install"))
+	}
+	fmt.Fprintf(w, "  %s\n", util.Cyan(install.RunScriptCommand(pm, "dev")))
+
+	warnings := summaryWarnings(cfg, result, util.Cyan)
+	if len(warnings) > 0 {
+		fmt.Fprintln(w)
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "%s %s\n", util.Warning("Warning:"), warning)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// summaryWarnings lists what is left to do after creation, formatting
+// commands with code
+func summaryWarnings(cfg *config.Config, result *creationResult, code func(string) string) []string {
+	pm := cfg.PackageManager
+
+	var warnings []string
+	if result.exampleStale {
+		warnings = append(warnings, "The example was copied from the local cache without checking for updates, it may be out of date.")
+	}
+	if len(result.rejected) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Unsafe entries of the example were skipped (%d), check that the project is complete.", len(result.rejected)))
+	}
+	if !result.installed {
+		warnings = append(warnings, fmt.Sprintf("Dependencies were not installed. Run %s before starting the app.", code(pm+" install")))
+	}
+	if result.offline {
+		warnings = append(warnings, fmt.Sprintf("Dependencies were installed offline from the local cache. Run %s when you are back online to pick up updates.", code(pm+" install")))
+	}
+	if !result.gitInitialized {
+		reason := "--skip-git was used"
+		if result.gitNotice != "" {
+			reason = result.gitNotice
+		}
+		warnings = append(warnings, fmt.Sprintf("No git repository was initialized: %s.", reason))
+	}
+	return warnings
+}
+
+// describeFeatures lists the chosen options in words
+func describeFeatures(cfg *config.Config) []string {
+	var features []string
+
+	if cfg.TypeScript {
+		features = append(features, "TypeScript")
+	} else {
+		features = append(features, "JavaScript")
+	}
+	if cfg.APIOnly {
+		features = append(features, "API only")
+	} else if cfg.Tailwind {
+		features = append(features, "Tailwind CSS")
+	}
+
+	switch cfg.Linter {
+	case "eslint":
+		features = append(features, "ESLint")
+	case "biome":
+		features = append(features, "Biome")
+	}
+
+	features = append(features, "App Router")
+	switch cfg.Bundler {
+	case "webpack":
+		features = append(features, "Webpack")
+	case "rspack":
+		features = append(features, "Rspack")
+	default:
+		features = append(features, "Turbopack")
+	}
+
+	if cfg.ReactCompiler && !cfg.APIOnly {
+		features = append(features, "React Compiler")
+	}
+	if cfg.SrcDir {
+		features = append(features, "src/ directory")
+	}
+	if cfg.ImportAlias != "" && cfg.ImportAlias != "@/*" {
+		features = append(features, "import alias "+cfg.ImportAlias)
+	}
+	if cfg.EmptyTemplate {
+		features = append(features, "empty template")
+	}
+
+	return features
+}
+
+// cdPath returns the path to cd into the project from the working
+// directory, or "" when already inside it
+func cdPath(projectPath string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return projectPath
+	}
+	if cwd == projectPath {
+		return ""
+	}
+
+	dir := projectPath
+	if rel, err := filepath.Rel(cwd, projectPath); err == nil && !strings.HasPrefix(rel, "..") {
+		dir = rel
+	}
+	if strings.ContainsAny(dir, " \t'\"") {
+		dir = `"` + dir + `"`
+	}
+	return dir
+}
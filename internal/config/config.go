@@ -3,41 +3,92 @@ package config
 // Config holds all configuration for project creation
 type Config struct {
 	// Project Identity
-	ProjectName string
-	ProjectPath string // Absolute path
+	ProjectName string `json:"projectName"`
+	ProjectPath string `json:"projectPath"` // Absolute path
 
 	// Language & Framework
-	TypeScript bool
-	AppRouter  bool // Always true - Pages Router removed
-	APIOnly    bool // API-only project (no React)
+	TypeScript bool `json:"typescript"`
+	AppRouter  bool `json:"appRouter"` // Always true - Pages Router removed
+	APIOnly    bool `json:"apiOnly"`   // API-only project (no React)
 
 	// Styling
-	Tailwind bool
+	Tailwind bool `json:"tailwind"`
 
 	// Linting & Formatting
-	Linter string // "eslint", "biome", or "none"
+	Linter string `json:"linter"` // "eslint", "biome", or "none"
 
 	// Project Structure
-	SrcDir        bool
-	ImportAlias   string // Default: "@/*"
-	EmptyTemplate bool   // Minimal template
+	SrcDir        bool   `json:"srcDir"`
+	ImportAlias   string `json:"importAlias"`   // Default: "@/*"
+	EmptyTemplate bool   `json:"emptyTemplate"` // Minimal template
 
 	// Bundler
-	Bundler string // "turbopack", "webpack", or "rspack"
+	Bundler string `json:"bundler"` // "turbopack", "webpack", or "rspack"
 
 	// Features
-	ReactCompiler bool
+	ReactCompiler bool `json:"reactCompiler"`
 
 	// Package Manager
-	PackageManager string // "npm", "pnpm", "yarn", or "bun"
-	SkipInstall    bool
+	PackageManager string `json:"packageManager"` // "npm", "pnpm", "yarn", or "bun"
+	SkipInstall    bool   `json:"skipInstall"`
 
 	// Git
-	SkipGit bool
+	SkipGit bool `json:"skipGit"`
 
 	// Example Mode
-	Example     string // Example name or GitHub URL
-	ExamplePath string // Path within repo (for subdirectories)
+	Example     string `json:"example"`     // Example name, GitHub URL, or local directory or archive
+	ExamplePath string `json:"examplePath"` // Path within repo (for subdirectories)
+}
+
+// Field keys identify Config options, matching the preference keys where
+// a preference exists
+const (
+	FieldProjectPath    = "projectPath"
+	FieldTypeScript     = "typescript"
+	FieldAPIOnly        = "apiOnly"
+	FieldTailwind       = "tailwind"
+	FieldLinter         = "linter"
+	FieldSrcDir         = "srcDir"
+	FieldImportAlias    = "importAlias"
+	FieldEmptyTemplate  = "emptyTemplate"
+	FieldBundler        = "bundler"
+	FieldReactCompiler  = "reactCompiler"
+	FieldPackageManager = "packageManager"
+	FieldSkipInstall    = "skipInstall"
+	FieldSkipGit        = "skipGit"
+	FieldExample        = "example"
+	FieldExamplePath    = "examplePath"
+)
+
+// Fields lists every field key in the order they are reported
+var Fields = []string{
+	FieldProjectPath,
+	FieldTypeScript,
+	FieldAPIOnly,
+	FieldTailwind,
+	FieldLinter,
+	FieldSrcDir,
+	FieldImportAlias,
+	FieldEmptyTemplate,
+	FieldBundler,
+	FieldReactCompiler,
+	FieldPackageManager,
+	FieldSkipInstall,
+	FieldSkipGit,
+	FieldExample,
+	FieldExamplePath,
+}
+
+// PreferenceFields are the fields saved preferences can provide
+var PreferenceFields = []string{
+	FieldTypeScript,
+	FieldLinter,
+	FieldTailwind,
+	FieldSrcDir,
+	FieldImportAlias,
+	FieldEmptyTemplate,
+	FieldReactCompiler,
+	FieldSkipGit,
 }
 
 // New creates a new Config with default values
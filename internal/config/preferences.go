@@ -102,8 +102,9 @@ func ClearPreferences() error {
 	return os.Remove(filepath.Join(configDir, "preferences.json"))
 }
 
-// MergeConfig merges CLI flags with preferences and defaults
-func MergeConfig(flags *Config, prefs *Preferences) *Config {
+// MergeConfig merges CLI flags with preferences and defaults. Only the
+// flag fields named in explicit are applied over preferences.
+func MergeConfig(flags *Config, prefs *Preferences, explicit map[string]bool) *Config {
 	defaults := DefaultConfig()
 
 	if flags == nil {
@@ -115,16 +116,23 @@ func MergeConfig(flags *Config, prefs *Preferences) *Config {
 
 	if prefs != nil {
 		result.TypeScript = prefs.TypeScript
-		result.Linter = prefs.Linter
+		if prefs.Linter != "" {
+			result.Linter = prefs.Linter
+		}
 		result.Tailwind = prefs.Tailwind
 		result.AppRouter = prefs.AppRouter
 		result.SrcDir = prefs.SrcDir
-		result.ImportAlias = prefs.ImportAlias
+		if prefs.ImportAlias != "" {
+			result.ImportAlias = prefs.ImportAlias
+		}
 		result.EmptyTemplate = prefs.EmptyTemplate
 		result.SkipGit = prefs.DisableGit
 		result.ReactCompiler = prefs.ReactCompiler
 	}
 
+	// App Router is the only supported router
+	result.AppRouter = true
+
 	// CLI flags take highest priority
 	if flags.ProjectName != "" {
 		result.ProjectName = flags.ProjectName
@@ -132,6 +140,48 @@ func MergeConfig(flags *Config, prefs *Preferences) *Config {
 	if flags.ProjectPath != "" {
 		result.ProjectPath = flags.ProjectPath
 	}
+	if explicit[FieldTypeScript] {
+		result.TypeScript = flags.TypeScript
+	}
+	if explicit[FieldAPIOnly] {
+		result.APIOnly = flags.APIOnly
+	}
+	if explicit[FieldTailwind] {
+		result.Tailwind = flags.Tailwind
+	}
+	if explicit[FieldLinter] {
+		result.Linter = flags.Linter
+	}
+	if explicit[FieldSrcDir] {
+		result.SrcDir = flags.SrcDir
+	}
+	if explicit[FieldImportAlias] {
+		result.ImportAlias = flags.ImportAlias
+	}
+	if explicit[FieldEmptyTemplate] {
+		result.EmptyTemplate = flags.EmptyTemplate
+	}
+	if explicit[FieldBundler] {
+		result.Bundler = flags.Bundler
+	}
+	if explicit[FieldReactCompiler] {
+		result.ReactCompiler = flags.ReactCompiler
+	}
+	if explicit[FieldPackageManager] {
+		result.PackageManager = flags.PackageManager
+	}
+	if explicit[FieldSkipInstall] {
+		result.SkipInstall = flags.SkipInstall
+	}
+	if explicit[FieldSkipGit] {
+		result.SkipGit = flags.SkipGit
+	}
+	if explicit[FieldExample] {
+		result.Example = flags.Example
+	}
+	if explicit[FieldExamplePath] {
+		result.ExamplePath = flags.ExamplePath
+	}
 
 	return result
 }
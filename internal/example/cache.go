@@ -0,0 +1,246 @@
+package example
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Files of a cache entry directory
+const (
+	cacheArchiveName = "archive.tar.gz"
+	cacheMetaName    = "meta.json"
+)
+
+// cacheRoot is the directory wrapped around the files of a cached
+// archive, so it extracts like a GitHub tarball
+const cacheRoot = "example"
+
+// Cache stores the extracted directory of each downloaded example, keyed
+// by repository, ref and path
+type Cache struct {
+	Dir string
+}
+
+// CacheEntry describes a cached example
+type CacheEntry struct {
+	Username  string    `json:"username"`
+	Repo      string    `json:"repo"`
+	Ref       string    `json:"ref"`
+	Path      string    `json:"path"`
+	ETag      string    `json:"etag"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Size      int64     `json:"-"` // Size of the archive on disk
+	dir       string
+}
+
+// RepoInfo returns the repository directory the entry was downloaded from
+func (e *CacheEntry) RepoInfo() *RepoInfo {
+	return &RepoInfo{Username: e.Username, Repo: e.Repo, Branch: e.Ref, FilePath: e.Path}
+}
+
+// DefaultCacheDir returns the examples cache in the user cache directory
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "better-next-app", "examples"), nil
+}
+
+// NewCache opens the cache in the default directory
+func NewCache() (*Cache, error) {
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Dir: dir}, nil
+}
+
+// entryDir returns the directory of the entry for repo
+func (c *Cache) entryDir(repo *RepoInfo) string {
+	sum := sha256.Sum256([]byte(repo.Username + "/" + repo.Repo + "\x00" + repo.Branch + "\x00" + repo.FilePath))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:12]))
+}
+
+// Lookup returns the entry for repo, or nil when it is not cached
+func (c *Cache) Lookup(repo *RepoInfo) (*CacheEntry, error) {
+	entry, err := readCacheEntry(c.entryDir(repo))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return entry, err
+}
+
+// Entries returns every cached example, most recently fetched first
+func (c *Cache) Entries() ([]*CacheEntry, error) {
+	dirs, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*CacheEntry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		// Skip incomplete or foreign directories
+		if entry, err := readCacheEntry(filepath.Join(c.Dir, dir.Name())); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FetchedAt.After(entries[j].FetchedAt)
+	})
+	return entries, nil
+}
+
+// Clear removes every cached example and returns the bytes freed
+func (c *Cache) Clear() (int64, error) {
+	var size int64
+	filepath.WalkDir(c.Dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// readCacheEntry reads the metadata of the entry in dir
+func readCacheEntry(dir string) (*CacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cacheMetaName))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filepath.Join(dir, cacheArchiveName))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{dir: dir, Size: info.Size()}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// open opens the cached archive
+func (e *CacheEntry) open() (*os.File, error) {
+	return os.Open(filepath.Join(e.dir, cacheArchiveName))
+}
+
+// cacheWriter builds a new cache entry while an example is extracted.
+// The entry replaces the previous one only when commit is called.
+type cacheWriter struct {
+	entry *CacheEntry
+	tmp   string
+	file  *os.File
+	gz    *gzip.Writer
+	tar   *tar.Writer
+}
+
+// create starts a new entry for repo with the ETag of its download
+func (c *Cache) create(repo *RepoInfo, etag string) (*cacheWriter, error) {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(c.Dir, ".tmp-")
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(filepath.Join(tmp, cacheArchiveName))
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(file)
+	return &cacheWriter{
+		entry: &CacheEntry{
+			Username: repo.Username,
+			Repo:     repo.Repo,
+			Ref:      repo.Branch,
+			Path:     repo.FilePath,
+			ETag:     etag,
+			dir:      c.entryDir(repo),
+		},
+		tmp:  tmp,
+		file: file,
+		gz:   gz,
+		tar:  tar.NewWriter(gz),
+	}, nil
+}
+
+// add appends an extracted file of size bytes, read from r, to the entry
+func (w *cacheWriter) add(rel string, r io.Reader, size int64, mode fs.FileMode) error {
+	header := &tar.Header{
+		Name:     cacheRoot + "/" + rel,
+		Mode:     int64(mode.Perm()),
+		Size:     size,
+		Typeflag: tar.TypeReg,
+	}
+	if err := w.tar.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(w.tar, r)
+	return err
+}
+
+// addSymlink appends an extracted symbolic link to the entry
+func (w *cacheWriter) addSymlink(rel, target string) error {
+	return w.tar.WriteHeader(&tar.Header{
+		Name:     cacheRoot + "/" + rel,
+		Mode:     0777,
+		Linkname: target,
+		Typeflag: tar.TypeSymlink,
+	})
+}
+
+// commit replaces the previous entry with the new one
+func (w *cacheWriter) commit() error {
+	err := errors.Join(w.tar.Close(), w.gz.Close(), w.file.Close())
+	if err == nil {
+		w.entry.FetchedAt = time.Now().UTC()
+		var data []byte
+		if data, err = json.MarshalIndent(w.entry, "", "  "); err == nil {
+			err = os.WriteFile(filepath.Join(w.tmp, cacheMetaName), data, 0644)
+		}
+	}
+	if err == nil {
+		if err = os.RemoveAll(w.entry.dir); err == nil {
+			err = os.Rename(w.tmp, w.entry.dir)
+		}
+	}
+	if err != nil {
+		os.RemoveAll(w.tmp)
+	}
+	return err
+}
+
+// abort discards the new entry, keeping the previous one
+func (w *cacheWriter) abort() {
+	w.tar.Close()
+	w.gz.Close()
+	w.file.Close()
+	os.RemoveAll(w.tmp)
+}
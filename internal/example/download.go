@@ -0,0 +1,232 @@
+package example
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// Default GitHub endpoints
+const (
+	DefaultAPIURL      = "https://api.github.com"
+	DefaultCodeloadURL = "https://codeload.github.com"
+)
+
+// userAgent identifies the CLI to GitHub, which requires a User-Agent
+const userAgent = "better-next-app"
+
+// Timeouts of the default HTTP client. They bound connecting and waiting
+// for a response, not streaming its body, so large downloads still work.
+const (
+	dialTimeout           = 10 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	responseHeaderTimeout = 30 * time.Second
+)
+
+// defaultHTTPClient gives up on unresponsive networks, so the cached copy
+// of an example is used instead of hanging, and goes through the proxy
+// from HTTPS_PROXY and NO_PROXY
+var defaultHTTPClient = newHTTPClient()
+
+// newHTTPClient creates a client with the default timeouts
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+			TLSHandshakeTimeout:   tlsHandshakeTimeout,
+			ResponseHeaderTimeout: responseHeaderTimeout,
+			IdleConnTimeout:       90 * time.Second,
+			ForceAttemptHTTP2:     true,
+		},
+	}
+}
+
+// Client looks up and downloads examples from GitHub
+type Client struct {
+	APIURL      string       // Defaults to DefaultAPIURL
+	CodeloadURL string       // Defaults to DefaultCodeloadURL
+	RawURL      string       // Defaults to DefaultRawURL
+	HTTPClient  *http.Client // Defaults to a client with timeouts honouring HTTPS_PROXY and NO_PROXY
+	Cache       *Cache       // Downloads are not cached when nil
+}
+
+// NewClient creates a Client for the public GitHub endpoints, caching
+// downloads in the user cache directory when there is one
+func NewClient() *Client {
+	cache, _ := NewCache()
+	return &Client{
+		APIURL:      DefaultAPIURL,
+		CodeloadURL: DefaultCodeloadURL,
+		RawURL:      DefaultRawURL,
+		HTTPClient:  defaultHTTPClient,
+		Cache:       cache,
+	}
+}
+
+// DownloadOptions configure a download
+type DownloadOptions struct {
+	Offline bool // Use the cached copy without revalidating it
+}
+
+// Downloaded describes the extracted example
+type Downloaded struct {
+	Files     []string // Relative to the project root
+	FromCache bool
+	Stale     bool        // The cached copy could not be revalidated
+	Rejected  []Rejection // Unsafe entries that were skipped
+}
+
+// Download extracts repo.FilePath into the journal's root. A cached copy
+// is revalidated with its ETag and used when it is unchanged, when
+// offline, or when GitHub cannot be reached. Otherwise the repository
+// tarball is streamed and the directory is cached while it is extracted.
+func (c *Client) Download(ctx context.Context, repo *RepoInfo, journal *util.Journal, opts DownloadOptions) (*Downloaded, error) {
+	entry := c.lookupCache(repo)
+	if entry != nil && opts.Offline {
+		return extractCached(entry, journal, true)
+	}
+
+	header := http.Header{}
+	if entry != nil && entry.ETag != "" {
+		header.Set("If-None-Match", entry.ETag)
+	}
+
+	tarballURL := joinURL(c.codeloadURL(), repo.Username, repo.Repo, "tar.gz") + "/" + escapePath(repo.Branch)
+	resp, err := c.get(ctx, tarballURL, header)
+	var statusErr *statusError
+	switch {
+	case entry != nil && errors.As(err, &statusErr) && statusErr.code == http.StatusNotModified:
+		return extractCached(entry, journal, false)
+	case entry != nil && err != nil && !errors.As(err, &statusErr) && ctx.Err() == nil:
+		return extractCached(entry, journal, true)
+	case err != nil:
+		return nil, fmt.Errorf("failed to download %s: %w", repo, err)
+	}
+	defer resp.Body.Close()
+
+	x := newExtractor(repo.FilePath, journal)
+	if c.Cache != nil {
+		// Caching is best effort, the download works without it
+		if x.cache, err = c.Cache.create(repo, resp.Header.Get("ETag")); err != nil {
+			x.cache = nil
+		}
+	}
+
+	downloaded, err := x.extractTarGz(resp.Body, 1)
+	if x.cache != nil {
+		if err != nil {
+			x.cache.abort()
+		} else {
+			x.cache.commit()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", repo, err)
+	}
+	return downloaded, nil
+}
+
+// lookupCache returns the cached copy of repo, if any
+func (c *Client) lookupCache(repo *RepoInfo) *CacheEntry {
+	if c.Cache == nil {
+		return nil
+	}
+	entry, err := c.Cache.Lookup(repo)
+	if err != nil {
+		return nil
+	}
+	return entry
+}
+
+// extractCached extracts a cached example
+func extractCached(entry *CacheEntry, journal *util.Journal, stale bool) (*Downloaded, error) {
+	file, err := entry.open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	downloaded, err := ExtractTarGz(file, "", journal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the cached copy of %s: %w", entry.RepoInfo(), err)
+	}
+	downloaded.FromCache = true
+	downloaded.Stale = stale
+	return downloaded, nil
+}
+
+// apiURL builds a GitHub API URL from escaped path segments
+func (c *Client) apiURL(query url.Values, segments ...string) string {
+	apiURL := c.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	u := joinURL(apiURL, segments...)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c *Client) codeloadURL() string {
+	if c.CodeloadURL == "" {
+		return DefaultCodeloadURL
+	}
+	return c.CodeloadURL
+}
+
+// get sends a GET request with the extra header and returns the response
+// when it succeeded, or a *statusError for any other status
+func (c *Client) get(ctx context.Context, rawURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, &statusError{url: rawURL, code: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// joinURL appends escaped path segments to base
+func joinURL(base string, segments ...string) string {
+	u := strings.TrimRight(base, "/")
+	for _, segment := range segments {
+		u += "/" + url.PathEscape(segment)
+	}
+	return u
+}
+
+// escapePath escapes each segment of a slash separated path
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
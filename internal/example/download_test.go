@@ -0,0 +1,390 @@
+package example
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadExtractsOnlyFilePath(t *testing.T) {
+	tarball := gzipBytes(t, buildTar(t, []tarEntry{
+		file("repo-main/README.md", "# repo"),
+		file("repo-main/examples/foo/package.json", "{}"),
+		file("repo-main/examples/foo/app/page.tsx", "export default 1"),
+		file("repo-main/examples/foobar/package.json", "{}"),
+		file("repo-main/examples/bar/package.json", "{}"),
+	}))
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write(tarball)
+	}))
+	defer server.Close()
+	client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client()}
+
+	root, journal := sandbox(t)
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "feature/x", FilePath: "examples/foo"}
+	downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requested != "/user/repo/tar.gz/feature/x" {
+		t.Errorf("requested %s", requested)
+	}
+	files := slices.Sorted(slices.Values(downloaded.Files))
+	if want := []string{"app/page.tsx", "package.json"}; !slices.Equal(files, want) {
+		t.Errorf("Files = %v, want %v", files, want)
+	}
+	if downloaded.FromCache {
+		t.Error("FromCache = true without a cache")
+	}
+	for _, name := range []string{"README.md", "examples", "foobar"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			t.Errorf("%s was extracted", name)
+		}
+	}
+}
+
+func TestDownloadMissingDirectory(t *testing.T) {
+	tarball := gzipBytes(t, buildTar(t, []tarEntry{file("repo-main/README.md", "# repo")}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarball)
+	}))
+	defer server.Close()
+	client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client()}
+
+	_, journal := sandbox(t)
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+	if _, err := client.Download(context.Background(), repo, journal, DownloadOptions{}); err == nil {
+		t.Fatal("Download() succeeded without files in examples/foo")
+	}
+}
+
+// cachingServer serves tarball with an ETag, answering 304 to a request
+// that already has it. It records the If-None-Match header of each request.
+func cachingServer(t *testing.T, tarball []byte, etag string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(tarball)
+	}))
+	t.Cleanup(server.Close)
+	return server, &sent
+}
+
+// readProjectFile returns the content of name in root
+func readProjectFile(t *testing.T, root, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDownloadRevalidatesCachedCopy(t *testing.T) {
+	tarball := gzipBytes(t, buildTar(t, []tarEntry{
+		file("repo-main/examples/foo/package.json", `{"name": "foo"}`),
+	}))
+	server, sent := cachingServer(t, tarball, `"v1"`)
+	client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client(), Cache: &Cache{Dir: t.TempDir()}}
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+
+	_, journal := sandbox(t)
+	downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloaded.FromCache {
+		t.Error("FromCache = true on the first download")
+	}
+
+	root, journal := sandbox(t)
+	downloaded, err = client.Download(context.Background(), repo, journal, DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"", `"v1"`}; !slices.Equal(*sent, want) {
+		t.Errorf("If-None-Match = %q, want %q", *sent, want)
+	}
+	if !downloaded.FromCache || downloaded.Stale {
+		t.Errorf("FromCache = %v, Stale = %v after a 304, want true, false", downloaded.FromCache, downloaded.Stale)
+	}
+	if got := readProjectFile(t, root, "package.json"); got != `{"name": "foo"}` {
+		t.Errorf("package.json = %q", got)
+	}
+}
+
+func TestDownloadReplacesChangedCachedCopy(t *testing.T) {
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+	cache := &Cache{Dir: t.TempDir()}
+
+	for i, body := range []string{"v1", "v2"} {
+		tarball := gzipBytes(t, buildTar(t, []tarEntry{file("repo-main/examples/foo/page.tsx", body)}))
+		server, sent := cachingServer(t, tarball, `"`+body+`"`)
+		client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client(), Cache: cache}
+
+		root, journal := sandbox(t)
+		downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if downloaded.FromCache {
+			t.Errorf("download %d: FromCache = true for a changed example", i)
+		}
+		if got := readProjectFile(t, root, "page.tsx"); got != body {
+			t.Errorf("download %d: page.tsx = %q, want %q", i, got, body)
+		}
+		if i == 1 && (*sent)[0] != `"v1"` {
+			t.Errorf("If-None-Match = %q, want the previous ETag", (*sent)[0])
+		}
+	}
+
+	entry, err := cache.Lookup(repo)
+	if err != nil || entry == nil {
+		t.Fatalf("Lookup() = %v, %v", entry, err)
+	}
+	if entry.ETag != `"v2"` {
+		t.Errorf("cached ETag = %q, want the new one", entry.ETag)
+	}
+}
+
+func TestDownloadUsesStaleCachedCopy(t *testing.T) {
+	tarball := gzipBytes(t, buildTar(t, []tarEntry{file("repo-main/examples/foo/page.tsx", "cached")}))
+	server, sent := cachingServer(t, tarball, `"v1"`)
+	client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client(), Cache: &Cache{Dir: t.TempDir()}}
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+
+	_, journal := sandbox(t)
+	if _, err := client.Download(context.Background(), repo, journal, DownloadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("offline", func(t *testing.T) {
+		requests := len(*sent)
+		root, journal := sandbox(t)
+		downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{Offline: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != requests {
+			t.Error("a request was sent while offline")
+		}
+		if !downloaded.FromCache || !downloaded.Stale {
+			t.Errorf("FromCache = %v, Stale = %v, want true, true", downloaded.FromCache, downloaded.Stale)
+		}
+		if got := readProjectFile(t, root, "page.tsx"); got != "cached" {
+			t.Errorf("page.tsx = %q", got)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server.Close()
+		_, journal := sandbox(t)
+		downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !downloaded.FromCache || !downloaded.Stale {
+			t.Errorf("FromCache = %v, Stale = %v, want true, true", downloaded.FromCache, downloaded.Stale)
+		}
+	})
+}
+
+func TestDownloadOfflineWithoutCachedCopy(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := &Client{CodeloadURL: server.URL, HTTPClient: server.Client(), Cache: &Cache{Dir: t.TempDir()}}
+
+	_, journal := sandbox(t)
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+	if _, err := client.Download(context.Background(), repo, journal, DownloadOptions{Offline: true}); err == nil {
+		t.Fatal("Download() succeeded offline without a cached copy")
+	}
+}
+
+func TestResolveGitHubFallsBackToCache(t *testing.T) {
+	cache := &Cache{Dir: t.TempDir()}
+	cached := []*RepoInfo{
+		officialRepoInfo("with-docker"),
+		{Username: "user", Repo: "repo", Branch: "main", FilePath: "apps/web"},
+		{Username: "user", Repo: "repo", Branch: "feature/x", FilePath: "examples/foo"},
+	}
+	for _, repo := range cached {
+		w, err := cache.create(repo, `"etag"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.add("package.json", strings.NewReader("{}"), 2, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// GitHub can't be reached
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := &Client{APIURL: server.URL, CodeloadURL: server.URL, HTTPClient: server.Client(), Cache: cache}
+
+	tests := []struct {
+		name        string
+		example     string
+		examplePath string
+		want        *RepoInfo
+	}{
+		{name: "official example", example: "with-docker", want: cached[0]},
+		{name: "default branch with an example path", example: "https://github.com/user/repo", examplePath: "apps/web", want: cached[1]},
+		{name: "tree URL", example: "https://github.com/user/repo/tree/feature/x/examples/foo", want: cached[2]},
+		{name: "uncached example", example: "with-mdx"},
+		{name: "uncached path", example: "https://github.com/user/repo", examplePath: "apps/docs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := client.ResolveGitHub(context.Background(), tt.example, tt.examplePath)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("ResolveGitHub() = %+v, want an error", *repo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *repo != *tt.want {
+				t.Errorf("ResolveGitHub() = %+v, want %+v", *repo, *tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheEntriesAndClear(t *testing.T) {
+	cache := &Cache{Dir: filepath.Join(t.TempDir(), "examples")}
+
+	entries, err := cache.Entries()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Entries() of a missing cache = %v, %v", entries, err)
+	}
+
+	repos := []*RepoInfo{
+		{Username: "user", Repo: "repo", Branch: "main", FilePath: "a"},
+		{Username: "user", Repo: "repo", Branch: "main", FilePath: "b"},
+	}
+	for _, repo := range repos {
+		w, err := cache.create(repo, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.add("page.tsx", strings.NewReader("export default 1"), 16, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A failed write leaves neither an entry nor its temporary directory
+	w, err := cache.create(&RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "c"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.abort()
+	if dirs, err := os.ReadDir(cache.Dir); err != nil || len(dirs) != len(repos) {
+		t.Errorf("cache directory holds %d entries after an aborted write, want %d", len(dirs), len(repos))
+	}
+
+	entries, err = cache.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	var size int64
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+		size += entry.Size
+	}
+	if want := []string{"b", "a"}; !slices.Equal(paths, want) {
+		t.Errorf("Entries() paths = %v, want most recent first %v", paths, want)
+	}
+
+	freed, err := cache.Clear()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed < size {
+		t.Errorf("Clear() freed %d bytes, want at least the %d of the archives", freed, size)
+	}
+	if _, err := os.Stat(cache.Dir); !os.IsNotExist(err) {
+		t.Errorf("cache directory still exists: %v", err)
+	}
+}
+
+func TestDownloadFallsBackWhenServerHangs(t *testing.T) {
+	tarball := gzipBytes(t, buildTar(t, []tarEntry{file("repo-main/examples/foo/page.tsx", "cached")}))
+	hang := make(chan struct{})
+	var hanging atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hanging.Load() {
+			// A captive portal or flaky network that never answers
+			<-hang
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(tarball)
+	}))
+	defer server.Close()
+	defer close(hang)
+
+	httpClient := newHTTPClient()
+	httpClient.Transport.(*http.Transport).ResponseHeaderTimeout = 100 * time.Millisecond
+	client := &Client{CodeloadURL: server.URL, HTTPClient: httpClient, Cache: &Cache{Dir: t.TempDir()}}
+	repo := &RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "examples/foo"}
+
+	_, journal := sandbox(t)
+	if _, err := client.Download(context.Background(), repo, journal, DownloadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	hanging.Store(true)
+	_, journal = sandbox(t)
+	start := time.Now()
+	downloaded, err := client.Download(context.Background(), repo, journal, DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !downloaded.FromCache || !downloaded.Stale {
+		t.Errorf("FromCache = %v, Stale = %v, want true, true", downloaded.FromCache, downloaded.Stale)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Download() took %v to give up", elapsed)
+	}
+}
+
+func TestDefaultHTTPClientTimeouts(t *testing.T) {
+	transport, ok := NewClient().HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("NewClient() doesn't use an *http.Transport")
+	}
+	if transport.Proxy == nil {
+		t.Error("the proxy from the environment is not used")
+	}
+	if transport.DialContext == nil || transport.TLSHandshakeTimeout == 0 || transport.ResponseHeaderTimeout == 0 {
+		t.Error("connecting or waiting for a response has no timeout")
+	}
+}
@@ -0,0 +1,453 @@
+package example
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// skippedDirs are never extracted from an example
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// Limits bound what a single example may extract
+type Limits struct {
+	MaxFiles int   // Files and links
+	MaxBytes int64 // Total size of the files
+}
+
+// DefaultLimits are generous for any Next.js example while stopping
+// archive bombs
+var DefaultLimits = Limits{
+	MaxFiles: 10000,
+	MaxBytes: 512 << 20,
+}
+
+// unsafePath is the reason for rejecting an entry outside the example
+const unsafePath = "the path is absolute or leaves the example"
+
+// Rejection is an archive entry that was refused
+type Rejection struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// entryType is the kind of an archive entry
+type entryType int
+
+const (
+	entryFile entryType = iota
+	entryDir
+	entrySymlink
+	entryHardlink
+	entryDevice
+	entryOther
+)
+
+// archiveEntry is an archive entry whose path already had its wrapping
+// directories stripped
+type archiveEntry struct {
+	name     string
+	typ      entryType
+	mode     fs.FileMode
+	linkname string    // Target of a link; hard link targets are stripped archive paths
+	body     io.Reader // Content of a file
+}
+
+// symlink is a link that is created once every file was written
+type symlink struct {
+	rel    string
+	target string
+}
+
+// extractor writes the archive entries below a directory of the archive
+// into the journal's root. Entries that would end up outside the root,
+// links leaving it and device files are refused and reported. No file is
+// written through a symbolic link.
+type extractor struct {
+	prefix   string // Archive directory to extract, with a trailing slash
+	journal  *util.Journal
+	limits   Limits
+	cache    *cacheWriter // Receives a copy of each file when set
+	files    []string
+	written  map[string]bool
+	symlinks []symlink
+	size     int64
+	rejected []Rejection
+}
+
+// newExtractor extracts the entries below dir, "" for the whole archive
+func newExtractor(dir string, journal *util.Journal) *extractor {
+	prefix := strings.Trim(path.Clean("/"+dir), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &extractor{
+		prefix:  prefix,
+		journal: journal,
+		limits:  DefaultLimits,
+		written: map[string]bool{},
+	}
+}
+
+// ExtractTarGz extracts dir of a gzipped tarball into the journal's root.
+// The top-level directory GitHub wraps around the repository is stripped
+// first.
+func ExtractTarGz(r io.Reader, dir string, journal *util.Journal) (*Downloaded, error) {
+	return newExtractor(dir, journal).extractTarGz(r, 1)
+}
+
+// extractTarGz extracts a gzipped tarball, removing the first strip
+// components of each path
+func (x *extractor) extractTarGz(r io.Reader, strip int) (*Downloaded, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if isMacOSMetadata(header.Name) {
+			continue
+		}
+		if _, ok := cleanArchivePath(header.Name); !ok {
+			x.reject(header.Name, unsafePath)
+			continue
+		}
+
+		e := archiveEntry{
+			name:     stripComponents(header.Name, strip),
+			mode:     header.FileInfo().Mode(),
+			linkname: header.Linkname,
+			body:     tr,
+		}
+		switch header.Typeflag {
+		case tar.TypeReg:
+			e.typ = entryFile
+		case tar.TypeDir:
+			e.typ = entryDir
+		case tar.TypeSymlink:
+			e.typ = entrySymlink
+		case tar.TypeLink:
+			e.typ = entryHardlink
+			// Unsafe targets are kept as is for addHardlink to reject
+			if _, ok := cleanArchivePath(header.Linkname); ok {
+				e.linkname = stripComponents(header.Linkname, strip)
+			}
+		case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+			e.typ = entryDevice
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			e.typ = entryOther
+		}
+
+		if err := x.add(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return x.finish()
+}
+
+// entryTypeOf classifies a file mode from a zip archive or a directory
+func entryTypeOf(mode fs.FileMode) entryType {
+	switch {
+	case mode.IsRegular():
+		return entryFile
+	case mode.IsDir():
+		return entryDir
+	case mode&fs.ModeSymlink != 0:
+		return entrySymlink
+	case mode&(fs.ModeDevice|fs.ModeCharDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0:
+		return entryDevice
+	}
+	return entryOther
+}
+
+// add extracts an entry when it is below the prefix
+func (x *extractor) add(e archiveEntry) error {
+	name, ok := cleanArchivePath(e.name)
+	if !ok {
+		x.reject(e.name, unsafePath)
+		return nil
+	}
+	if !strings.HasPrefix(name, x.prefix) {
+		return nil
+	}
+
+	rel := strings.TrimPrefix(name, x.prefix)
+	if rel == "" {
+		return nil
+	}
+	for _, dir := range strings.Split(rel, "/") {
+		if skippedDirs[dir] {
+			return nil
+		}
+	}
+
+	switch e.typ {
+	case entryDir:
+		// Directories are created for the files they contain
+		return nil
+	case entryFile:
+		return x.addFile(rel, e.mode, e.body)
+	case entrySymlink:
+		return x.addSymlink(rel, e.linkname)
+	case entryHardlink:
+		return x.addHardlink(rel, e.linkname)
+	case entryDevice:
+		x.reject(rel, "device files and named pipes are not allowed")
+	default:
+		x.reject(rel, "unsupported file type")
+	}
+	return nil
+}
+
+// addFile streams a regular file to disk, keeping only its executable
+// bits. No more than the remaining size limit is read from r.
+func (x *extractor) addFile(rel string, mode fs.FileMode, r io.Reader) error {
+	if err := x.count(); err != nil {
+		return err
+	}
+	if x.throughSymlink(rel) {
+		x.reject(rel, "the path runs through a symbolic link")
+		return nil
+	}
+
+	perm := fs.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+	n, err := x.journal.WriteFileFrom(x.path(rel), io.LimitReader(r, x.limits.MaxBytes-x.size+1), perm)
+	x.size += n
+	if err != nil {
+		return err
+	}
+	if x.size > x.limits.MaxBytes {
+		return fmt.Errorf("the example is larger than %s", util.FormatSize(x.limits.MaxBytes))
+	}
+	return x.finishFile(rel, perm)
+}
+
+// addHardlink copies the file a hard link points to, which must have
+// been extracted before
+func (x *extractor) addHardlink(rel, linkname string) error {
+	target, ok := cleanArchivePath(linkname)
+	if !ok || !strings.HasPrefix(target, x.prefix) {
+		x.reject(rel, "the hard link points outside the example")
+		return nil
+	}
+	target = strings.TrimPrefix(target, x.prefix)
+	if target == rel {
+		// Copying the file onto itself would truncate it
+		return nil
+	}
+	if !x.written[target] {
+		x.reject(rel, "the hard link points to a file that was not extracted")
+		return nil
+	}
+
+	path := x.path(target)
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return x.addFile(rel, info.Mode(), file)
+}
+
+// addSymlink queues a link whose target stays inside the example
+func (x *extractor) addSymlink(rel, target string) error {
+	if target == "" || path.IsAbs(target) || filepath.IsAbs(target) || strings.Contains(target, `\`) {
+		x.reject(rel, "the symbolic link has an absolute or invalid target")
+		return nil
+	}
+	if _, ok := cleanArchivePath(path.Join(path.Dir(rel), target)); !ok {
+		x.reject(rel, "the symbolic link points outside the example")
+		return nil
+	}
+	if err := x.count(); err != nil {
+		return err
+	}
+
+	x.symlinks = append(x.symlinks, symlink{rel: rel, target: target})
+	return nil
+}
+
+// finishFile sets the mode of a written file and copies it to the cache
+func (x *extractor) finishFile(rel string, perm fs.FileMode) error {
+	// WriteFileFrom doesn't change the mode of an existing file
+	if err := os.Chmod(x.path(rel), perm); err != nil {
+		return err
+	}
+
+	if x.cache != nil {
+		if err := x.cacheFile(rel, perm); err != nil {
+			return err
+		}
+	}
+	if !x.written[rel] {
+		x.files = append(x.files, rel)
+	}
+	x.written[rel] = true
+	return nil
+}
+
+// cacheFile copies a written file from disk to the cache
+func (x *extractor) cacheFile(rel string, perm fs.FileMode) error {
+	file, err := os.Open(x.path(rel))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return x.cache.add(rel, file, info.Size(), perm)
+}
+
+// count counts one more file against the limit
+func (x *extractor) count() error {
+	if len(x.files)+len(x.symlinks) >= x.limits.MaxFiles {
+		return fmt.Errorf("the example has more than %d files", x.limits.MaxFiles)
+	}
+	return nil
+}
+
+// throughSymlink reports whether rel or one of its parent directories
+// is a symbolic link on disk
+func (x *extractor) throughSymlink(rel string) bool {
+	p := x.journal.Root()
+	for _, component := range strings.Split(rel, "/") {
+		p = filepath.Join(p, component)
+		info, err := os.Lstat(p)
+		if err != nil {
+			return false
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the location of rel on disk
+func (x *extractor) path(rel string) string {
+	return filepath.Join(x.journal.Root(), filepath.FromSlash(rel))
+}
+
+// reject reports an entry that was not extracted
+func (x *extractor) reject(name, reason string) {
+	x.rejected = append(x.rejected, Rejection{Path: name, Reason: reason})
+}
+
+// finish creates the symbolic links and returns what was extracted,
+// failing when there were no files. Links that resolve outside the root
+// through other links are removed again.
+func (x *extractor) finish() (*Downloaded, error) {
+	var created []symlink
+	for _, link := range x.symlinks {
+		if x.written[link.rel] || x.throughSymlink(link.rel) {
+			x.reject(link.rel, "the path runs through a symbolic link or is taken by a file")
+			continue
+		}
+		if err := x.journal.Symlink(filepath.FromSlash(link.target), x.path(link.rel)); err != nil {
+			x.reject(link.rel, fmt.Sprintf("the symbolic link could not be created: %v", err))
+			continue
+		}
+		created = append(created, link)
+	}
+
+	root, err := filepath.EvalSymlinks(x.journal.Root())
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range created {
+		// Dangling links are kept, their target was checked when queued
+		if resolved, err := filepath.EvalSymlinks(x.path(link.rel)); err == nil && !within(root, resolved) {
+			os.Remove(x.path(link.rel))
+			x.reject(link.rel, "the symbolic link points outside the example")
+			continue
+		}
+		if x.cache != nil {
+			if err := x.cache.addSymlink(link.rel, link.target); err != nil {
+				return nil, err
+			}
+		}
+		x.files = append(x.files, link.rel)
+	}
+
+	if len(x.files) == 0 {
+		if x.prefix != "" {
+			return nil, fmt.Errorf("the archive has no files in %s", strings.TrimSuffix(x.prefix, "/"))
+		}
+		return nil, fmt.Errorf("the archive has no files")
+	}
+	return &Downloaded{Files: x.files, Rejected: x.rejected}, nil
+}
+
+// within reports whether path is root or below it
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cleanArchivePath cleans a slash separated archive path and reports
+// whether it stays inside the archive
+func cleanArchivePath(name string) (string, bool) {
+	if name == "" {
+		return "", true
+	}
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || strings.Contains(name, `\`) {
+		return "", false
+	}
+
+	clean := path.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", false
+	}
+	if clean == "." {
+		return "", true
+	}
+	return clean, true
+}
+
+// stripComponents removes the first n components of an archive path
+func stripComponents(name string, n int) string {
+	name = strings.TrimPrefix(name, "./")
+	for ; n > 0; n-- {
+		i := strings.Index(name, "/")
+		if i < 0 {
+			return ""
+		}
+		name = name[i+1:]
+	}
+	return name
+}
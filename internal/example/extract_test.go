@@ -0,0 +1,450 @@
+package example
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// tarEntry is a tarball entry built by the tests
+type tarEntry struct {
+	name     string
+	typ      byte
+	mode     int64
+	linkname string
+	body     string
+}
+
+func file(name, body string) tarEntry {
+	return tarEntry{name: name, typ: tar.TypeReg, mode: 0644, body: body}
+}
+
+func link(name, target string) tarEntry {
+	return tarEntry{name: name, typ: tar.TypeSymlink, linkname: target}
+}
+
+// buildTar returns an uncompressed tarball of entries
+func buildTar(t testing.TB, entries []tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typ, Mode: e.mode, Linkname: e.linkname}
+		if e.typ == tar.TypeReg {
+			header.Size = int64(len(e.body))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// gzipBytes compresses data
+func gzipBytes(t testing.TB, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// sandbox returns a project root inside a directory that must stay empty
+// apart from the root itself
+func sandbox(t testing.TB) (string, *util.Journal) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "project")
+	journal, err := util.NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, journal
+}
+
+// assertContained fails when anything was written next to root or a
+// link in root resolves outside of it
+func assertContained(t testing.TB, root string) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(root) {
+			t.Fatalf("%s was written outside the project", entry.Name())
+		}
+	}
+
+	real, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return
+	}
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+		if resolved, err := filepath.EvalSymlinks(p); err == nil && !within(real, resolved) {
+			t.Fatalf("%s resolves to %s outside the project", p, resolved)
+		}
+		return nil
+	})
+}
+
+// rejectedPaths lists the paths of the rejected entries
+func rejectedPaths(downloaded *Downloaded) []string {
+	var paths []string
+	for _, rejection := range downloaded.Rejected {
+		paths = append(paths, rejection.Path)
+	}
+	return paths
+}
+
+func TestExtractTarGzRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		entries  []tarEntry
+		rejected []string
+		missing  []string // Must not exist in the project
+	}{
+		{
+			name:     "parent traversal",
+			entries:  []tarEntry{file("w/../../evil", "x")},
+			rejected: []string{"w/../../evil"},
+		},
+		{
+			name:     "traversal below the wrapper",
+			entries:  []tarEntry{file("w/a/../../../evil", "x")},
+			rejected: []string{"w/a/../../../evil"},
+		},
+		{
+			name:     "absolute path",
+			entries:  []tarEntry{file("/etc/evil", "x")},
+			rejected: []string{"/etc/evil"},
+			missing:  []string{"etc/evil", "evil"},
+		},
+		{
+			name:     "symlink escaping with ../..",
+			entries:  []tarEntry{link("w/out", "../../etc")},
+			rejected: []string{"out"},
+			missing:  []string{"out"},
+		},
+		{
+			name:     "absolute symlink",
+			entries:  []tarEntry{link("w/passwd", "/etc/passwd")},
+			rejected: []string{"passwd"},
+			missing:  []string{"passwd"},
+		},
+		{
+			name: "symlink escaping through another link",
+			entries: []tarEntry{
+				link("w/d/up", ".."),
+				link("w/e", "d/up/.."),
+			},
+			rejected: []string{"e"},
+			missing:  []string{"e"},
+		},
+		{
+			name: "symlink replacing an extracted directory",
+			entries: []tarEntry{
+				link("w/dir", "."),
+				file("w/dir/through", "x"),
+			},
+			rejected: []string{"dir"},
+		},
+		{
+			name: "hard link outside the prefix",
+			dir:  "ex",
+			entries: []tarEntry{
+				file("w/secret", "x"),
+				{name: "w/ex/hard", typ: tar.TypeLink, linkname: "w/secret"},
+			},
+			rejected: []string{"hard"},
+			missing:  []string{"hard"},
+		},
+		{
+			name: "hard link escaping",
+			entries: []tarEntry{
+				{name: "w/hard", typ: tar.TypeLink, linkname: "../etc/passwd"},
+			},
+			rejected: []string{"hard"},
+			missing:  []string{"hard"},
+		},
+		{
+			name:     "character device",
+			entries:  []tarEntry{{name: "w/null", typ: tar.TypeChar}},
+			rejected: []string{"null"},
+			missing:  []string{"null"},
+		},
+		{
+			name:     "block device",
+			entries:  []tarEntry{{name: "w/sda", typ: tar.TypeBlock}},
+			rejected: []string{"sda"},
+			missing:  []string{"sda"},
+		},
+		{
+			name:     "fifo",
+			entries:  []tarEntry{{name: "w/pipe", typ: tar.TypeFifo}},
+			rejected: []string{"pipe"},
+			missing:  []string{"pipe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, journal := sandbox(t)
+			entries := append([]tarEntry{file("w/ex/package.json", "{}")}, tt.entries...)
+
+			downloaded, err := ExtractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, entries))), tt.dir, journal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range tt.rejected {
+				if !slices.Contains(rejectedPaths(downloaded), path) {
+					t.Errorf("%s was not rejected, rejected %v", path, downloaded.Rejected)
+				}
+			}
+			for _, path := range tt.missing {
+				if _, err := os.Lstat(filepath.Join(root, path)); err == nil {
+					t.Errorf("%s was extracted", path)
+				}
+			}
+			assertContained(t, root)
+		})
+	}
+}
+
+func TestExtractTarGzKeepsSafeLinks(t *testing.T) {
+	root, journal := sandbox(t)
+	entries := []tarEntry{
+		file("w/package.json", "{}"),
+		link("w/alias", "package.json"),
+		link("w/d/up", ".."),
+		{name: "w/copy", typ: tar.TypeLink, linkname: "w/package.json"},
+	}
+
+	downloaded, err := ExtractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, entries))), "", journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(downloaded.Rejected) > 0 {
+		t.Fatalf("rejected %v", downloaded.Rejected)
+	}
+	for _, name := range []string{"alias", "copy"} {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil || string(data) != "{}" {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	assertContained(t, root)
+}
+
+func TestExtractTarGzPreservesExecutableBit(t *testing.T) {
+	root, journal := sandbox(t)
+	entries := []tarEntry{
+		{name: "w/run.sh", typ: tar.TypeReg, mode: 0750, body: "#!/bin/sh\n"},
+		{name: "w/data.txt", typ: tar.TypeReg, mode: 0600, body: "x"},
+	}
+
+	if _, err := ExtractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, entries))), "", journal); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]fs.FileMode{"run.sh": 0755, "data.txt": 0644} {
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestExtractTarGzLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		entries []tarEntry
+		want    string
+	}{
+		{
+			name:    "too many files",
+			limits:  Limits{MaxFiles: 2, MaxBytes: 1 << 20},
+			entries: []tarEntry{file("w/a", "a"), file("w/b", "b"), file("w/c", "c")},
+			want:    "more than 2 files",
+		},
+		{
+			name:    "too many links",
+			limits:  Limits{MaxFiles: 2, MaxBytes: 1 << 20},
+			entries: []tarEntry{file("w/a", "a"), link("w/b", "a"), link("w/c", "a")},
+			want:    "more than 2 files",
+		},
+		{
+			name:    "too large",
+			limits:  Limits{MaxFiles: 10, MaxBytes: 4},
+			entries: []tarEntry{file("w/a", "abc"), file("w/b", "de")},
+			want:    "larger than",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, journal := sandbox(t)
+			x := newExtractor("", journal)
+			x.limits = tt.limits
+
+			_, err := x.extractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, tt.entries))), 1)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("at the limits", func(t *testing.T) {
+		_, journal := sandbox(t)
+		x := newExtractor("", journal)
+		x.limits = Limits{MaxFiles: 2, MaxBytes: 5}
+
+		entries := []tarEntry{file("w/a", "abc"), file("w/b", "de")}
+		if _, err := x.extractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, entries))), 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// endlessReader returns zeros forever, counting the bytes read
+type endlessReader struct {
+	read int64
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	clear(p)
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestExtractorStreamsFiles(t *testing.T) {
+	t.Run("stops reading past the limit", func(t *testing.T) {
+		_, journal := sandbox(t)
+		x := newExtractor("", journal)
+		x.limits = Limits{MaxFiles: 10, MaxBytes: 1 << 20}
+
+		r := &endlessReader{}
+		err := x.addFile("big.bin", 0644, r)
+		if err == nil || !strings.Contains(err.Error(), "larger than") {
+			t.Fatalf("err = %v, want the example to be too large", err)
+		}
+		// One byte past the limit tells that the file is too large
+		if r.read > x.limits.MaxBytes+1 {
+			t.Errorf("read %d bytes with a limit of %d", r.read, x.limits.MaxBytes)
+		}
+	})
+
+	t.Run("hard link to itself", func(t *testing.T) {
+		root, journal := sandbox(t)
+		entries := []tarEntry{file("w/a", "abc"), {name: "w/a", typ: tar.TypeLink, linkname: "w/a"}}
+		if _, err := ExtractTarGz(bytes.NewReader(gzipBytes(t, buildTar(t, entries))), "", journal); err != nil {
+			t.Fatal(err)
+		}
+		if got := readProjectFile(t, root, "a"); got != "abc" {
+			t.Errorf("a = %q, want %q", got, "abc")
+		}
+	})
+}
+
+// tarSeeds are hostile tarballs the fuzzers start from
+func tarSeeds(t testing.TB) [][]byte {
+	return [][]byte{
+		buildTar(t, []tarEntry{file("w/a", "a"), file("w/../../evil", "x"), file("/abs", "x")}),
+		buildTar(t, []tarEntry{link("w/d/up", ".."), link("w/e", "d/up/.."), file("w/e/x", "x")}),
+		buildTar(t, []tarEntry{link("w/dir", "."), file("w/dir/f", "x"), link("w/out", "../../x")}),
+		buildTar(t, []tarEntry{file("w/a", "a"), {name: "w/h", typ: tar.TypeLink, linkname: "w/a"}}),
+		buildTar(t, []tarEntry{{name: "w/p", typ: tar.TypeFifo}, {name: "w/c", typ: tar.TypeChar}}),
+	}
+}
+
+func FuzzExtractTarGz(f *testing.F) {
+	for _, seed := range tarSeeds(f) {
+		f.Add(seed, 1)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, strip int) {
+		root, journal := sandbox(t)
+		x := newExtractor("", journal)
+		x.limits = Limits{MaxFiles: 100, MaxBytes: 1 << 20}
+
+		x.extractTarGz(bytes.NewReader(gzipBytes(t, data)), strip%3)
+		assertContained(t, root)
+	})
+}
+
+// buildZip converts a tarball into a zip archive with the same entries
+func buildZip(t testing.TB, tarball []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	tr := tar.NewReader(bytes.NewReader(tarball))
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		fh, err := zip.FileInfoHeader(header.FileInfo())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fh.Name = header.Name
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeSymlink {
+			w.Write([]byte(header.Linkname))
+		} else {
+			buf := new(bytes.Buffer)
+			buf.ReadFrom(tr)
+			w.Write(buf.Bytes())
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func FuzzExtractZip(f *testing.F) {
+	for _, seed := range tarSeeds(f) {
+		f.Add(buildZip(f, seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		archive := filepath.Join(t.TempDir(), "example.zip")
+		if err := os.WriteFile(archive, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		root, journal := sandbox(t)
+
+		(&archiveSource{file: archive}).extractZip(journal)
+		assertContained(t, root)
+	})
+}
@@ -0,0 +1,183 @@
+package example
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// scpLikeURL matches the scp-like git syntax, e.g. git@host:org/repo.git
+var scpLikeURL = regexp.MustCompile(`^[\w.+-]+@[\w.-]+:[^/]`)
+
+// gitSchemes are URL schemes only used by git remotes
+var gitSchemes = []string{"ssh://", "git://", "git+ssh://", "git+https://", "git+http://", "git+file://"}
+
+// IsGitURL reports whether example is a git remote to clone rather than
+// a GitHub URL downloaded through codeload: an ssh or scp-like remote, or
+// an http(s) URL on another host or with a #ref
+func IsGitURL(example string) bool {
+	if scpLikeURL.MatchString(example) {
+		return true
+	}
+	for _, scheme := range gitSchemes {
+		if strings.HasPrefix(example, scheme) {
+			return true
+		}
+	}
+	if !IsURL(example) {
+		return false
+	}
+
+	u, err := url.Parse(example)
+	if err != nil {
+		return false
+	}
+	return !githubHosts[strings.ToLower(u.Host)] || u.Fragment != ""
+}
+
+// resolveGit splits a git URL into the remote and the ref after #
+func resolveGit(example, examplePath string) (Source, error) {
+	remote, ref, _ := strings.Cut(example, "#")
+	remote = strings.TrimPrefix(remote, "git+")
+	if remote == "" {
+		return nil, fmt.Errorf("invalid example URL %q: the repository is missing", example)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid example URL %q: invalid ref %q", example, ref)
+	}
+
+	return &gitSource{
+		example: example,
+		remote:  remote,
+		ref:     ref,
+		dir:     strings.Trim(path.Clean("/"+examplePath), "/"),
+	}, nil
+}
+
+// ErrAuthRequired is returned when a git remote asks for credentials
+var ErrAuthRequired = errors.New("authentication required")
+
+// authFailures are git and ssh messages for a remote that needs
+// credentials it wasn't given
+var authFailures = []string{
+	"terminal prompts disabled",
+	"could not read Username",
+	"could not read Password",
+	"Authentication failed",
+	"Permission denied (publickey",
+	"Host key verification failed",
+}
+
+// gitEnv keeps git from prompting for credentials, so a private remote
+// fails instead of waiting on a prompt nobody sees. ssh runs in batch mode
+// unless the user configured their own ssh command.
+func gitEnv() []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never"}
+	if os.Getenv("GIT_SSH_COMMAND") == "" && os.Getenv("GIT_SSH") == "" {
+		if output, _ := util.RunCommand("git", "config", "--get", "core.sshCommand"); strings.TrimSpace(output) == "" {
+			env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+		}
+	}
+	return env
+}
+
+// gitSource is a directory of a repository fetched with git
+type gitSource struct {
+	example string
+	remote  string
+	ref     string // Branch, tag or commit, "" for the remote's HEAD
+	dir     string // Directory within the repository, "" for all of it
+}
+
+func (s *gitSource) String() string {
+	str := s.remote
+	if s.ref != "" {
+		str += "#" + s.ref
+	}
+	if s.dir != "" {
+		str += ":" + s.dir
+	}
+	return str
+}
+
+func (s *gitSource) Remote() bool {
+	return true
+}
+
+// Extract fetches only the ref's last commit, checks out only the
+// example directory into a temporary clone and copies it without .git
+func (s *gitSource) Extract(ctx context.Context, journal *util.Journal, _ DownloadOptions) (*Downloaded, error) {
+	if !util.CommandExists("git") {
+		return nil, fmt.Errorf("git is required to fetch %s", s.remote)
+	}
+
+	clone, err := os.MkdirTemp("", "better-next-app-example-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(clone)
+
+	ref := s.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", s.remote},
+	}
+	if s.dir != "" {
+		steps = append(steps, []string{"sparse-checkout", "set", "--", s.dir})
+	}
+	steps = append(steps,
+		[]string{"fetch", "--quiet", "--depth", "1", "--filter=blob:none", "origin", ref},
+		[]string{"checkout", "--quiet", "FETCH_HEAD"},
+	)
+
+	env := gitEnv()
+	for _, args := range steps {
+		if err := s.runGit(ctx, clone, env, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	// The clone's .git is never copied, as the extractor skips it
+	dir := filepath.Join(clone, filepath.FromSlash(s.dir))
+	if info, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) || (err == nil && !info.IsDir()) {
+		return nil, &NotFoundError{Example: s.example, Reason: fmt.Sprintf("the repository has no directory %q", s.dir)}
+	}
+	return (&localDirSource{dir: dir}).Extract(ctx, journal, DownloadOptions{})
+}
+
+// runGit runs a git step in the clone, reporting a remote that needs
+// credentials as ErrAuthRequired
+func (s *gitSource) runGit(ctx context.Context, clone string, env []string, args ...string) error {
+	var stderr bytes.Buffer
+	err := util.RunCommandContextEnv(ctx, clone, env, nil, &stderr, "git", args...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	for _, failure := range authFailures {
+		if strings.Contains(msg, failure) {
+			return fmt.Errorf("failed to fetch %s: %w, set up a credential helper or an SSH key with access to %s", s, ErrAuthRequired, s.remote)
+		}
+	}
+	if msg != "" {
+		return fmt.Errorf("failed to fetch %s: git %s: %s", s, args[0], msg)
+	}
+	return fmt.Errorf("failed to fetch %s: git %s: %w", s, args[0], err)
+}
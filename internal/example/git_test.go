@@ -0,0 +1,213 @@
+package example
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// git runs git in dir with a fixed identity and no user config
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+// bareRepo creates a bare repository with examples on main and on the
+// feature/x branch and returns its path
+func bareRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	work := t.TempDir()
+	files := map[string]string{
+		"README.md":                   "# repo",
+		"examples/foo/package.json":   `{"name": "foo"}`,
+		"examples/foo/app/page.tsx":   "export default 1",
+		"examples/bar/package.json":   `{"name": "bar"}`,
+		"examples/foo/.gitattributes": "* text=auto",
+	}
+	for name, content := range files {
+		p := filepath.Join(work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, work, "init", "--quiet", "--initial-branch", "main")
+	git(t, work, "add", ".")
+	git(t, work, "commit", "--quiet", "-m", "Add examples")
+	git(t, work, "checkout", "--quiet", "-b", "feature/x")
+	if err := os.WriteFile(filepath.Join(work, "examples/foo/feature.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, work, "add", ".")
+	git(t, work, "commit", "--quiet", "-m", "Add feature")
+	git(t, work, "checkout", "--quiet", "main")
+
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	git(t, work, "clone", "--quiet", "--bare", work, bare)
+	return bare
+}
+
+func TestGitSourceExtract(t *testing.T) {
+	bare := bareRepo(t)
+
+	tests := []struct {
+		name        string
+		example     string
+		examplePath string
+		want        []string
+	}{
+		{
+			name:        "example path",
+			example:     "git+file://" + bare,
+			examplePath: "examples/foo",
+			want:        []string{".gitattributes", "app/page.tsx", "package.json"},
+		},
+		{
+			name:        "branch with a slash",
+			example:     "git+file://" + bare + "#feature/x",
+			examplePath: "examples/foo",
+			want:        []string{".gitattributes", "app/page.tsx", "feature.txt", "package.json"},
+		},
+		{
+			name:    "whole repository",
+			example: "git+file://" + bare,
+			want: []string{
+				"README.md",
+				"examples/bar/package.json",
+				"examples/foo/.gitattributes",
+				"examples/foo/app/page.tsx",
+				"examples/foo/package.json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := (&Client{}).Resolve(context.Background(), tt.example, tt.examplePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := source.(*gitSource); !ok {
+				t.Fatalf("Resolve() = %T, want a git source", source)
+			}
+
+			root, journal := sandbox(t)
+			downloaded, err := source.Extract(context.Background(), journal, DownloadOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files := slices.Sorted(slices.Values(downloaded.Files))
+			if !slices.Equal(files, tt.want) {
+				t.Errorf("Files = %v, want %v", files, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+				t.Error(".git was copied")
+			}
+		})
+	}
+}
+
+func TestGitSourceMissingPath(t *testing.T) {
+	bare := bareRepo(t)
+
+	source, err := (&Client{}).Resolve(context.Background(), "git+file://"+bare, "examples/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, journal := sandbox(t)
+	_, err = source.Extract(context.Background(), journal, DownloadOptions{})
+
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("err = %v, want a NotFoundError", err)
+	}
+	if _, err := os.Stat(root); err == nil {
+		t.Error("the project directory was created")
+	}
+}
+
+// isolateGit keeps the user's git config and askpass helpers from
+// answering credential requests
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_ASKPASS", "")
+	t.Setenv("SSH_ASKPASS", "")
+}
+
+func TestGitSourceAuthRequired(t *testing.T) {
+	isolateGit(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="private"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source, err := (&Client{}).Resolve(context.Background(), "git+"+server.URL+"/org/private.git", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	root, journal := sandbox(t)
+	_, err = source.Extract(ctx, journal, DownloadOptions{})
+
+	if !errors.Is(err, ErrAuthRequired) {
+		t.Fatalf("err = %v, want ErrAuthRequired", err)
+	}
+	if _, err := os.Stat(root); err == nil {
+		t.Error("the project directory was created")
+	}
+}
+
+func TestGitSourceCancelled(t *testing.T) {
+	isolateGit(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	source, err := (&Client{}).Resolve(context.Background(), "git+"+server.URL+"/org/slow.git", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, journal := sandbox(t)
+
+	start := time.Now()
+	_, err = source.Extract(ctx, journal, DownloadOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Extract() returned %v after the context was done", elapsed)
+	}
+}
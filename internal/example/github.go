@@ -0,0 +1,305 @@
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// The official examples live in the examples folder of the Next.js repo
+const (
+	officialUsername = "vercel"
+	officialRepo     = "next.js"
+	officialBranch   = "canary"
+	officialDir      = "examples"
+)
+
+// githubHosts are the hosts accepted in GitHub example URLs
+var githubHosts = map[string]bool{
+	"github.com":     true,
+	"www.github.com": true,
+}
+
+// RepoInfo identifies a directory of a GitHub repository at a ref
+type RepoInfo struct {
+	Username string
+	Repo     string
+	Branch   string // Branch, tag or commit
+	FilePath string // For subdirectories, "" for the repository root
+}
+
+// String formats the repository for messages as user/repo@ref:path
+func (r *RepoInfo) String() string {
+	s := r.Username + "/" + r.Repo
+	if r.Branch != "" {
+		s += "@" + r.Branch
+	}
+	if r.FilePath != "" {
+		s += ":" + r.FilePath
+	}
+	return s
+}
+
+// NotFoundError reports an example that could not be located
+type NotFoundError struct {
+	Example string
+	Reason  string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("could not locate the example %q: %s", e.Example, e.Reason)
+}
+
+// githubURL is a GitHub URL split into its parts. tree holds the path
+// segments after /tree/, which start with a ref that may contain slashes.
+type githubURL struct {
+	username string
+	repo     string
+	tree     []string
+}
+
+// IsURL reports whether example is a URL rather than an official
+// example name
+func IsURL(example string) bool {
+	return strings.HasPrefix(example, "http://") || strings.HasPrefix(example, "https://")
+}
+
+// parseGitHubURL splits a repository URL such as
+// https://github.com/user/repo/tree/branch/path without contacting GitHub
+func parseGitHubURL(raw string) (*githubURL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid example URL %q: %w", raw, err)
+	}
+	if !githubHosts[strings.ToLower(u.Host)] {
+		return nil, fmt.Errorf("invalid example URL %q: only GitHub repositories are supported", raw)
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) < 2 {
+		return nil, fmt.Errorf("invalid example URL %q: expected https://github.com/<user>/<repo>", raw)
+	}
+
+	parsed := &githubURL{
+		username: segments[0],
+		repo:     strings.TrimSuffix(segments[1], ".git"),
+	}
+
+	rest := segments[2:]
+	if len(rest) == 0 {
+		return parsed, nil
+	}
+	if rest[0] != "tree" || len(rest) < 2 {
+		return nil, fmt.Errorf("invalid example URL %q: expected https://github.com/<user>/<repo>/tree/<branch>/<path>", raw)
+	}
+	parsed.tree = rest[1:]
+	return parsed, nil
+}
+
+// ResolveGitHub turns an official example name or a GitHub URL into the
+// repository directory to download. examplePath selects a directory
+// within a repository given by URL. When GitHub cannot be reached, a
+// cached copy of the example is resolved instead.
+func (c *Client) ResolveGitHub(ctx context.Context, example, examplePath string) (*RepoInfo, error) {
+	examplePath = strings.Trim(path.Clean("/"+examplePath), "/")
+
+	repo, err := c.resolve(ctx, example, examplePath)
+	var urlErr *url.Error
+	if err != nil && errors.As(err, &urlErr) && ctx.Err() == nil {
+		if cached := c.resolveCached(example, examplePath); cached != nil {
+			return cached, nil
+		}
+	}
+	return repo, err
+}
+
+// resolve looks up the example on GitHub
+func (c *Client) resolve(ctx context.Context, example, examplePath string) (*RepoInfo, error) {
+	if !IsURL(example) {
+		if examplePath != "" {
+			return nil, fmt.Errorf("--example-path can only be used with an example URL")
+		}
+		return c.resolveOfficial(ctx, example)
+	}
+
+	parsed, err := parseGitHubURL(example)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &RepoInfo{Username: parsed.username, Repo: parsed.repo}
+	if len(parsed.tree) == 0 {
+		if repo.Branch, err = c.defaultBranch(ctx, repo); err != nil {
+			return nil, lookupError(example, err)
+		}
+	} else {
+		found, err := c.splitTree(ctx, repo, parsed.tree)
+		if err != nil {
+			return nil, lookupError(example, err)
+		}
+		if !found {
+			return nil, &NotFoundError{Example: example, Reason: "no branch, tag or commit matches the URL"}
+		}
+	}
+
+	if examplePath != "" {
+		repo.FilePath = strings.Trim(path.Join(repo.FilePath, examplePath), "/")
+	}
+	if repo.FilePath != "" {
+		if err := c.checkPath(ctx, repo); err != nil {
+			return nil, lookupError(example, err)
+		}
+	}
+	return repo, nil
+}
+
+// resolveCached finds the most recently cached copy of an example
+func (c *Client) resolveCached(example, examplePath string) *RepoInfo {
+	if c.Cache == nil {
+		return nil
+	}
+
+	if !IsURL(example) {
+		if entry := c.lookupCache(officialRepoInfo(example)); entry != nil {
+			return entry.RepoInfo()
+		}
+		return nil
+	}
+
+	parsed, err := parseGitHubURL(example)
+	if err != nil {
+		return nil
+	}
+	entries, err := c.Cache.Entries()
+	if err != nil {
+		return nil
+	}
+
+	// Without a ref in the URL any cached ref of the default path matches
+	want := path.Join(append(parsed.tree, examplePath)...)
+	for _, entry := range entries {
+		if entry.Username != parsed.username || entry.Repo != parsed.repo {
+			continue
+		}
+		if len(parsed.tree) == 0 && entry.Path == examplePath {
+			return entry.RepoInfo()
+		}
+		if len(parsed.tree) > 0 && path.Join(entry.Ref, entry.Path) == want {
+			return entry.RepoInfo()
+		}
+	}
+	return nil
+}
+
+// officialRepoInfo returns the location of an official example
+func officialRepoInfo(name string) *RepoInfo {
+	return &RepoInfo{
+		Username: officialUsername,
+		Repo:     officialRepo,
+		Branch:   officialBranch,
+		FilePath: officialDir + "/" + name,
+	}
+}
+
+// resolveOfficial checks that name is an example in the Next.js repo
+func (c *Client) resolveOfficial(ctx context.Context, name string) (*RepoInfo, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, &NotFoundError{Example: name, Reason: "not a valid example name"}
+	}
+
+	repo := officialRepoInfo(name)
+	if err := c.checkPath(ctx, repo); err != nil {
+		return nil, lookupError(name, err)
+	}
+	return repo, nil
+}
+
+// defaultBranch asks GitHub for the default branch of a repository
+func (c *Client) defaultBranch(ctx context.Context, repo *RepoInfo) (string, error) {
+	resp, err := c.get(ctx, c.apiURL(nil, "repos", repo.Username, repo.Repo), nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("invalid repository info: %w", err)
+	}
+	if info.DefaultBranch == "" {
+		return "", fmt.Errorf("the repository has no default branch")
+	}
+	return info.DefaultBranch, nil
+}
+
+// splitTree splits the segments after /tree/ into repo.Branch and
+// repo.FilePath and reports whether a ref matched. Branch names may
+// contain slashes, so the shortest prefix that GitHub knows as a ref wins.
+// Git refuses refs that are prefixes of each other, so at most one prefix
+// can match.
+func (c *Client) splitTree(ctx context.Context, repo *RepoInfo, tree []string) (bool, error) {
+	for i := 1; i <= len(tree); i++ {
+		segments := append([]string{"repos", repo.Username, repo.Repo, "commits"}, tree[:i]...)
+		resp, err := c.get(ctx, c.apiURL(nil, segments...), nil)
+		if errNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+
+		repo.Branch = strings.Join(tree[:i], "/")
+		repo.FilePath = strings.Join(tree[i:], "/")
+		return true, nil
+	}
+	return false, nil
+}
+
+// checkPath checks that the repository has repo.FilePath at repo.Branch
+func (c *Client) checkPath(ctx context.Context, repo *RepoInfo) error {
+	segments := append([]string{"repos", repo.Username, repo.Repo, "contents"}, strings.Split(repo.FilePath, "/")...)
+	resp, err := c.get(ctx, c.apiURL(url.Values{"ref": {repo.Branch}}, segments...), nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// lookupError turns a failed lookup into a NotFoundError when GitHub
+// answered that the example doesn't exist
+func lookupError(example string, err error) error {
+	if errNotFound(err) {
+		return &NotFoundError{Example: example, Reason: "it does not exist or is not public"}
+	}
+	return fmt.Errorf("failed to look up the example %q: %w", example, err)
+}
+
+// statusError is an unexpected HTTP response status
+type statusError struct {
+	url  string
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("GET %s: %d %s", e.url, e.code, http.StatusText(e.code))
+}
+
+// errNotFound reports whether err is a 404 or 422 from GitHub
+func errNotFound(err error) bool {
+	statusErr, ok := err.(*statusError)
+	return ok && (statusErr.code == http.StatusNotFound || statusErr.code == http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,115 @@
+package example
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// githubServer stands in for the GitHub API. routes maps a request path,
+// with the ref query appended after a "?", to the response body. Other
+// paths answer 404.
+func githubServer(t *testing.T, routes map[string]string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if ref := r.URL.Query().Get("ref"); ref != "" {
+			key += "?" + ref
+		}
+		body, ok := routes[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return &Client{APIURL: server.URL, CodeloadURL: server.URL, HTTPClient: server.Client()}
+}
+
+func TestResolveGitHub(t *testing.T) {
+	client := githubServer(t, map[string]string{
+		"/repos/vercel/next.js/contents/examples/with-docker?canary": "[]",
+		"/repos/user/repo":                                 `{"default_branch": "main"}`,
+		"/repos/user/repo/commits/feature/x":               "{}",
+		"/repos/user/repo/contents/examples/foo?feature/x": "[]",
+		"/repos/user/repo/contents/apps/web?main":          "[]",
+	})
+
+	tests := []struct {
+		name        string
+		example     string
+		examplePath string
+		want        RepoInfo
+	}{
+		{
+			name:    "official example",
+			example: "with-docker",
+			want:    RepoInfo{Username: "vercel", Repo: "next.js", Branch: "canary", FilePath: "examples/with-docker"},
+		},
+		{
+			name:    "branch with a slash",
+			example: "https://github.com/user/repo/tree/feature/x/examples/foo",
+			want:    RepoInfo{Username: "user", Repo: "repo", Branch: "feature/x", FilePath: "examples/foo"},
+		},
+		{
+			name:    "default branch",
+			example: "https://github.com/user/repo",
+			want:    RepoInfo{Username: "user", Repo: "repo", Branch: "main"},
+		},
+		{
+			name:        "default branch with an example path",
+			example:     "https://github.com/user/repo.git",
+			examplePath: "apps/web/",
+			want:        RepoInfo{Username: "user", Repo: "repo", Branch: "main", FilePath: "apps/web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := client.ResolveGitHub(context.Background(), tt.example, tt.examplePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *repo != tt.want {
+				t.Errorf("ResolveGitHub() = %+v, want %+v", *repo, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveGitHubNotFound(t *testing.T) {
+	client := githubServer(t, map[string]string{
+		"/repos/user/repo": `{"default_branch": "main"}`,
+	})
+
+	for _, example := range []string{
+		"missing-example",
+		"../secrets",
+		"https://github.com/user/repo/tree/no-such-branch",
+		"https://github.com/user/repo/tree/main/missing",
+		"https://github.com/user/private",
+	} {
+		t.Run(example, func(t *testing.T) {
+			_, err := client.ResolveGitHub(context.Background(), example, "")
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				t.Fatalf("err = %v, want a NotFoundError", err)
+			}
+		})
+	}
+}
+
+func TestParseGitHubURLRejectsOtherHosts(t *testing.T) {
+	for _, raw := range []string{
+		"https://gitlab.com/user/repo",
+		"https://github.com/user",
+		"https://github.com/user/repo/blob/main/README.md",
+	} {
+		if _, err := parseGitHubURL(raw); err == nil {
+			t.Errorf("parseGitHubURL(%q) succeeded", raw)
+		}
+	}
+}
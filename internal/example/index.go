@@ -0,0 +1,251 @@
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRawURL serves raw file contents from GitHub
+const DefaultRawURL = "https://raw.githubusercontent.com"
+
+// The examples index is refetched once it is older than indexTTL
+const (
+	indexTTL      = 24 * time.Hour
+	indexFileName = "index.json"
+)
+
+// Only the start of each README is fetched, by a few requests at a time
+const (
+	readmeBytes   = 1024
+	readmeWorkers = 8
+)
+
+// IndexEntry is an official example
+type IndexEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"` // First line of its README
+}
+
+// Index lists the official examples
+type Index struct {
+	FetchedAt time.Time    `json:"fetchedAt"`
+	Examples  []IndexEntry `json:"examples"`
+	Stale     bool         `json:"-"` // GitHub could not be reached to refresh it
+}
+
+// Index returns the official examples, from the cache while it is fresh.
+// refresh forces a new fetch. When GitHub cannot be reached, the cached
+// index is returned even when it is old and marked as stale.
+func (c *Client) Index(ctx context.Context, refresh bool) (*Index, error) {
+	cached := c.readIndex()
+	if cached != nil && !refresh && time.Since(cached.FetchedAt) < indexTTL {
+		return cached, nil
+	}
+
+	index, err := c.fetchIndex(ctx)
+	if err != nil {
+		if cached != nil && ctx.Err() == nil {
+			cached.Stale = true
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	c.writeIndex(index)
+	return index, nil
+}
+
+// fetchIndex lists the examples folder of the Next.js repo and reads the
+// first line of each example's README
+func (c *Client) fetchIndex(ctx context.Context) (*Index, error) {
+	resp, err := c.get(ctx, c.apiURL(url.Values{"ref": {officialBranch}}, "repos", officialUsername, officialRepo, "contents", officialDir), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var contents []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&contents); err != nil {
+		return nil, err
+	}
+
+	index := &Index{FetchedAt: time.Now().UTC()}
+	for _, item := range contents {
+		if item.Type == "dir" {
+			index.Examples = append(index.Examples, IndexEntry{Name: item.Name})
+		}
+	}
+	sort.Slice(index.Examples, func(i, j int) bool {
+		return index.Examples[i].Name < index.Examples[j].Name
+	})
+
+	jobs := make(chan *IndexEntry)
+	var wg sync.WaitGroup
+	for range readmeWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				entry.Description = c.readmeTitle(ctx, entry.Name)
+			}
+		}()
+	}
+	for i := range index.Examples {
+		jobs <- &index.Examples[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// readmeTitle returns the first line of an example's README, or "" when
+// it can't be read
+func (c *Client) readmeTitle(ctx context.Context, name string) string {
+	rawURL := c.RawURL
+	if rawURL == "" {
+		rawURL = DefaultRawURL
+	}
+	readmeURL := joinURL(rawURL, officialUsername, officialRepo, officialBranch, officialDir, name, "README.md")
+
+	resp, err := c.get(ctx, readmeURL, http.Header{"Range": {fmt.Sprintf("bytes=0-%d", readmeBytes-1)}})
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, readmeBytes))
+	if err != nil {
+		return ""
+	}
+	return firstLine(string(data))
+}
+
+// firstLine returns the first non-empty line of a README without its
+// heading markers
+func firstLine(readme string) string {
+	for _, line := range strings.Split(readme, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// Search returns the examples matching every word of term, best matches
+// first. Words match names by substring or as a subsequence, e.g. "twcss"
+// matches "with-tailwindcss", and descriptions by substring.
+func (idx *Index) Search(term string) []IndexEntry {
+	words := strings.Fields(strings.ToLower(term))
+
+	type match struct {
+		entry IndexEntry
+		score int
+	}
+	var matches []match
+	for _, entry := range idx.Examples {
+		total := 0
+		for _, word := range words {
+			score := matchScore(word, entry)
+			if score == 0 {
+				total = 0
+				break
+			}
+			total += score
+		}
+		if total > 0 || len(words) == 0 {
+			matches = append(matches, match{entry, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	results := make([]IndexEntry, len(matches))
+	for i, m := range matches {
+		results[i] = m.entry
+	}
+	return results
+}
+
+// matchScore rates how well a lower-case word matches an example, 0
+// meaning no match
+func matchScore(word string, entry IndexEntry) int {
+	name := strings.ToLower(entry.Name)
+	switch {
+	case name == word:
+		return 100
+	case strings.HasPrefix(name, word):
+		return 80
+	case strings.Contains(name, word):
+		return 60
+	case strings.Contains(strings.ToLower(entry.Description), word):
+		return 40
+	case isSubsequence(word, name):
+		return 20
+	}
+	return 0
+}
+
+// isSubsequence reports whether the characters of sub appear in s in order
+func isSubsequence(sub, s string) bool {
+	rest := []rune(sub)
+	for _, r := range s {
+		if len(rest) > 0 && r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// readIndex reads the cached index, or returns nil
+func (c *Client) readIndex() *Index {
+	if c.Cache == nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(c.Cache.Dir, indexFileName))
+	if err != nil {
+		return nil
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil
+	}
+	return &index
+}
+
+// writeIndex caches the index. Caching is best effort.
+func (c *Client) writeIndex(index *Index) {
+	if c.Cache == nil {
+		return
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Cache.Dir, 0755); err != nil {
+		return
+	}
+	tmp := filepath.Join(c.Cache.Dir, indexFileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, filepath.Join(c.Cache.Dir, indexFileName))
+}
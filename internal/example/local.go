@@ -0,0 +1,313 @@
+package example
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// archiveSuffixes are the archive types accepted as local examples
+var archiveSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+
+// IsLocal reports whether example refers to the local file system: a
+// file:// URL, an archive, or a path rather than an official example
+// name. Use ./name for a directory in the working directory.
+func IsLocal(example string) bool {
+	if strings.HasPrefix(example, "file://") {
+		return true
+	}
+	if IsURL(example) || IsGitURL(example) {
+		return false
+	}
+	return hasArchiveSuffix(example) ||
+		filepath.IsAbs(example) ||
+		strings.HasPrefix(example, ".") ||
+		strings.HasPrefix(example, "~") ||
+		strings.ContainsAny(example, `/\`)
+}
+
+// hasArchiveSuffix reports whether name looks like a supported archive
+func hasArchiveSuffix(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLocal checks that a local example exists
+func resolveLocal(example, examplePath string) (Source, error) {
+	p := example
+	if strings.HasPrefix(example, "file://") {
+		u, err := url.Parse(example)
+		if err != nil {
+			return nil, fmt.Errorf("invalid example URL %q: %w", example, err)
+		}
+		p = filepath.FromSlash(u.Path)
+	}
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		p = filepath.Join(home, p[1:])
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &NotFoundError{Example: example, Reason: "no such file or directory"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	examplePath = strings.Trim(path.Clean("/"+examplePath), "/")
+	switch {
+	case info.IsDir():
+		dir := filepath.Join(abs, filepath.FromSlash(examplePath))
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, &NotFoundError{Example: example, Reason: fmt.Sprintf("it has no directory %q", examplePath)}
+		}
+		return &localDirSource{dir: dir}, nil
+	case hasArchiveSuffix(abs):
+		return &archiveSource{file: abs, dir: examplePath}, nil
+	default:
+		return nil, fmt.Errorf("invalid example %q: expected a directory or a %s archive", example, strings.Join(archiveSuffixes, ", "))
+	}
+}
+
+// localDirSource is a directory on the local file system
+type localDirSource struct {
+	dir string
+}
+
+func (s *localDirSource) String() string {
+	return s.dir
+}
+
+func (s *localDirSource) Remote() bool {
+	return false
+}
+
+// Extract copies the directory's files
+func (s *localDirSource) Extract(ctx context.Context, journal *util.Journal, _ DownloadOptions) (*Downloaded, error) {
+	if rel, err := filepath.Rel(s.dir, journal.Root()); err == nil && !strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("the project directory %s is inside the example %s", journal.Root(), s.dir)
+	}
+
+	x := newExtractor("", journal)
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() && p != s.dir && skippedDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		e := archiveEntry{name: filepath.ToSlash(rel), typ: entryTypeOf(info.Mode()), mode: info.Mode()}
+		switch e.typ {
+		case entrySymlink:
+			if e.linkname, err = os.Readlink(p); err != nil {
+				return err
+			}
+			e.linkname = filepath.ToSlash(e.linkname)
+		case entryFile:
+			file, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			e.body = file
+		}
+		return x.add(e)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %s: %w", s.dir, err)
+	}
+	return x.finish()
+}
+
+// archiveSource is a .tar.gz, .tgz or .zip archive on the local file
+// system. A single top-level directory wrapping all files is stripped.
+type archiveSource struct {
+	file string
+	dir  string // Directory within the archive, "" for all of it
+}
+
+func (s *archiveSource) String() string {
+	if s.dir == "" {
+		return s.file
+	}
+	return s.file + ":" + s.dir
+}
+
+func (s *archiveSource) Remote() bool {
+	return false
+}
+
+func (s *archiveSource) Extract(_ context.Context, journal *util.Journal, _ DownloadOptions) (*Downloaded, error) {
+	var downloaded *Downloaded
+	var err error
+	if strings.HasSuffix(strings.ToLower(s.file), ".zip") {
+		downloaded, err = s.extractZip(journal)
+	} else {
+		downloaded, err = s.extractTarGz(journal)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", s, err)
+	}
+	return downloaded, nil
+}
+
+// extractZip extracts the archive as a zip file. The content of a
+// symbolic link entry is its target.
+func (s *archiveSource) extractZip(journal *util.Journal) (*Downloaded, error) {
+	archive, err := zip.OpenReader(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	strip := wrapperDepth(names)
+
+	x := newExtractor(s.dir, journal)
+	for _, f := range archive.File {
+		if isMacOSMetadata(f.Name) {
+			continue
+		}
+		if _, ok := cleanArchivePath(f.Name); !ok {
+			x.reject(f.Name, unsafePath)
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		e := archiveEntry{name: stripComponents(f.Name, strip), typ: entryTypeOf(f.Mode()), mode: f.Mode(), body: rc}
+		if e.typ == entrySymlink {
+			var target []byte
+			if target, err = io.ReadAll(io.LimitReader(rc, 4096)); err == nil {
+				e.linkname = string(target)
+			}
+		}
+		if err == nil {
+			err = x.add(e)
+		}
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return x.finish()
+}
+
+// extractTarGz extracts the archive as a gzipped tarball. The archive is
+// read twice, first to find a wrapping directory.
+func (s *archiveSource) extractTarGz(journal *util.Journal) (*Downloaded, error) {
+	names, err := s.tarNames()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return newExtractor(s.dir, journal).extractTarGz(file, wrapperDepth(names))
+}
+
+// tarNames lists the regular files of the tarball
+func (s *archiveSource) tarNames() ([]string, error) {
+	file, err := os.Open(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+}
+
+// wrapperDepth returns 1 when every archive path is inside the same
+// top-level directory, and 0 otherwise
+func wrapperDepth(names []string) int {
+	var top string
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "./")
+		if isMacOSMetadata(name) || strings.HasSuffix(name, "/") {
+			continue
+		}
+		i := strings.Index(name, "/")
+		if i < 0 || (top != "" && name[:i] != top) {
+			return 0
+		}
+		top = name[:i]
+	}
+	if top == "" {
+		return 0
+	}
+	return 1
+}
+
+// isMacOSMetadata reports whether name is resource fork data added by
+// the macOS archive utility
+func isMacOSMetadata(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/")
+}
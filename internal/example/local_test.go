@@ -0,0 +1,211 @@
+package example
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+func TestIsLocal(t *testing.T) {
+	tests := []struct {
+		example string
+		want    bool
+	}{
+		{"./my-example", true},
+		{"../my-example", true},
+		{".", true},
+		{"~", true},
+		{"~/examples/foo", true},
+		{"/abs/example", true},
+		{"file:///abs/example", true},
+		{"examples/foo", true},
+		{"foo.zip", true},
+		{"foo.tar.gz", true},
+		{"FOO.TGZ", true},
+		{"with-tailwindcss", false},
+		{"https://github.com/user/repo", false},
+		{"https://example.com/foo.zip", false},
+		{"git@github.com:user/repo.git", false},
+		{"git+file:///srv/repo.git", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.example, func(t *testing.T) {
+			if got := IsLocal(tt.example); got != tt.want {
+				t.Errorf("IsLocal(%q) = %v, want %v", tt.example, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFiles creates files in dir, keyed by slash separated path
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveLocal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	writeFiles(t, home, map[string]string{
+		"repo/apps/web/package.json": "{}",
+		"repo/README.md":             "# repo",
+		"example.zip":                "",
+		"notes.txt":                  "",
+	})
+	repo := filepath.Join(home, "repo")
+
+	tests := []struct {
+		name        string
+		example     string
+		examplePath string
+		want        Source
+		notFound    bool
+	}{
+		{name: "directory", example: repo, want: &localDirSource{dir: repo}},
+		{name: "example path", example: repo, examplePath: "apps/web/", want: &localDirSource{dir: filepath.Join(repo, "apps", "web")}},
+		{name: "example path escaping the directory", example: repo, examplePath: "../../apps/web", want: &localDirSource{dir: filepath.Join(repo, "apps", "web")}},
+		{name: "home directory", example: "~/repo", want: &localDirSource{dir: repo}},
+		{name: "file URL", example: "file://" + filepath.ToSlash(repo), want: &localDirSource{dir: repo}},
+		{name: "archive", example: filepath.Join(home, "example.zip"), examplePath: "apps/web", want: &archiveSource{file: filepath.Join(home, "example.zip"), dir: "apps/web"}},
+		{name: "missing example path", example: repo, examplePath: "apps/docs", notFound: true},
+		{name: "file as example path", example: repo, examplePath: "README.md", notFound: true},
+		{name: "missing directory", example: filepath.Join(home, "missing"), notFound: true},
+		{name: "unsupported file", example: filepath.Join(home, "notes.txt")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := resolveLocal(tt.example, tt.examplePath)
+			if tt.want == nil {
+				var notFound *NotFoundError
+				if err == nil {
+					t.Fatalf("resolveLocal() = %v, want an error", source)
+				}
+				if errors.As(err, &notFound) != tt.notFound {
+					t.Errorf("err = %v, NotFoundError %v", err, tt.notFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source.String() != tt.want.String() {
+				t.Errorf("resolveLocal() = %s, want %s", source, tt.want)
+			}
+		})
+	}
+}
+
+// extractedFiles returns the sorted files of downloaded
+func extractedFiles(downloaded *Downloaded) []string {
+	return slices.Sorted(slices.Values(downloaded.Files))
+}
+
+func TestLocalDirSourceCopiesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json":                   "{}",
+		"app/page.tsx":                   "export default 1",
+		".git/HEAD":                      "ref: refs/heads/main",
+		"node_modules/next/package.json": "{}",
+		"app/node_modules/x/index.js":    "",
+	})
+
+	root, journal := sandbox(t)
+	downloaded, err := (&localDirSource{dir: dir}).Extract(context.Background(), journal, DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"app/page.tsx", "package.json"}; !slices.Equal(extractedFiles(downloaded), want) {
+		t.Errorf("Files = %v, want %v", extractedFiles(downloaded), want)
+	}
+	if got := readProjectFile(t, root, "app/page.tsx"); got != "export default 1" {
+		t.Errorf("app/page.tsx = %q", got)
+	}
+}
+
+func TestLocalDirSourceRefusesProjectInsideExample(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"package.json": "{}"})
+
+	for _, root := range []string{dir, filepath.Join(dir, "my-app")} {
+		t.Run(filepath.Base(root), func(t *testing.T) {
+			journal, err := util.NewJournal(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := (&localDirSource{dir: dir}).Extract(context.Background(), journal, DownloadOptions{}); err == nil {
+				t.Fatal("Extract() copied the example into itself")
+			}
+		})
+	}
+}
+
+func TestArchiveSourceStripsWrapperDirectory(t *testing.T) {
+	wrapped := buildTar(t, []tarEntry{
+		file("example-main/package.json", "{}"),
+		file("example-main/apps/web/page.tsx", "export default 1"),
+		file("__MACOSX/example-main/._package.json", ""),
+	})
+	flat := buildTar(t, []tarEntry{
+		file("package.json", "{}"),
+		file("apps/web/page.tsx", "export default 1"),
+	})
+	twoDirs := buildTar(t, []tarEntry{
+		file("a/package.json", "{}"),
+		file("b/page.tsx", "export default 1"),
+	})
+
+	tests := []struct {
+		name    string
+		tarball []byte
+		dir     string
+		want    []string
+	}{
+		{name: "wrapper", tarball: wrapped, want: []string{"apps/web/page.tsx", "package.json"}},
+		{name: "wrapper with a directory", tarball: wrapped, dir: "apps/web", want: []string{"page.tsx"}},
+		{name: "no wrapper", tarball: flat, want: []string{"apps/web/page.tsx", "package.json"}},
+		{name: "two top-level directories", tarball: twoDirs, want: []string{"a/package.json", "b/page.tsx"}},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []struct {
+			ext  string
+			data []byte
+		}{
+			{".tar.gz", gzipBytes(t, tt.tarball)},
+			{".zip", buildZip(t, tt.tarball)},
+		} {
+			t.Run(tt.name+format.ext, func(t *testing.T) {
+				archive := filepath.Join(t.TempDir(), "example"+format.ext)
+				if err := os.WriteFile(archive, format.data, 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				_, journal := sandbox(t)
+				downloaded, err := (&archiveSource{file: archive, dir: tt.dir}).Extract(context.Background(), journal, DownloadOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(extractedFiles(downloaded), tt.want) {
+					t.Errorf("Files = %v, want %v", extractedFiles(downloaded), tt.want)
+				}
+			})
+		}
+	}
+}
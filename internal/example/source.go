@@ -0,0 +1,53 @@
+package example
+
+import (
+	"context"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// Source is a resolved example that can be extracted into a project
+type Source interface {
+	// String describes the source for messages
+	String() string
+	// Remote reports whether extracting needs the network
+	Remote() bool
+	// Extract writes the example into the journal's root
+	Extract(ctx context.Context, journal *util.Journal, opts DownloadOptions) (*Downloaded, error)
+}
+
+// Resolve finds the example given to --example: a git remote, a local
+// directory, file:// URL or archive, an official example name, or a
+// GitHub URL. examplePath selects a directory within it.
+func (c *Client) Resolve(ctx context.Context, example, examplePath string) (Source, error) {
+	if IsGitURL(example) {
+		return resolveGit(example, examplePath)
+	}
+	if IsLocal(example) {
+		return resolveLocal(example, examplePath)
+	}
+
+	repo, err := c.ResolveGitHub(ctx, example, examplePath)
+	if err != nil {
+		return nil, err
+	}
+	return &githubSource{client: c, repo: repo}, nil
+}
+
+// githubSource is a directory of a GitHub repository
+type githubSource struct {
+	client *Client
+	repo   *RepoInfo
+}
+
+func (s *githubSource) String() string {
+	return s.repo.String()
+}
+
+func (s *githubSource) Remote() bool {
+	return true
+}
+
+func (s *githubSource) Extract(ctx context.Context, journal *util.Journal, opts DownloadOptions) (*Downloaded, error) {
+	return s.client.Download(ctx, s.repo, journal, opts)
+}
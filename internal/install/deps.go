@@ -0,0 +1,169 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// outputTailSize is how much of the install output is kept for errors
+const outputTailSize = 8 * 1024
+
+// offlineArgs are the install flags that make each package manager
+// resolve packages from its local cache instead of the registry. Yarn 2+
+// has no such flag and is configured through offlineEnv instead.
+var offlineArgs = map[string][]string{
+	"npm":  {"--offline"},
+	"pnpm": {"--offline"},
+	"yarn": {"--offline"},
+	"bun":  {"--prefer-offline"},
+}
+
+// offlineEnv enables the offline mode of Yarn 2+
+var offlineEnv = []string{"YARN_ENABLE_OFFLINE_MODE=1"}
+
+// yarnVersion returns the version of the yarn used in dir, which depends
+// on the project with corepack
+var yarnVersion = func(dir string) (string, error) {
+	return util.RunCommandInDir(dir, "yarn", "--version")
+}
+
+// networkErrors are output fragments that show the registry was unreachable
+var networkErrors = []string{
+	"ENOTFOUND", "EAI_AGAIN", "ETIMEDOUT", "ECONNREFUSED", "ECONNRESET",
+	"ENETUNREACH", "ERR_PNPM_META_FETCH_FAIL", "getaddrinfo", "network",
+}
+
+// cacheMissErrors are output fragments that show an offline install
+// needed a package missing from the local cache
+var cacheMissErrors = []string{
+	"ENOTCACHED", "ERR_PNPM_NO_OFFLINE_META", "ERR_PNPM_NO_OFFLINE_TARBALL",
+	"Couldn't find any versions", "not found in cache", "offline mode",
+}
+
+// InstallOptions control how dependencies are installed
+type InstallOptions struct {
+	Offline bool      // Install from the local cache only
+	Out     io.Writer // Receives the package manager's output as it runs
+}
+
+// InstallError represents a failed package manager run
+type InstallError struct {
+	Command string
+	Output  string // The last part of the command's output
+	Offline bool
+	Err     error
+}
+
+func (e *InstallError) Error() string {
+	return fmt.Sprintf("%s has failed: %v", e.Command, e.Err)
+}
+
+func (e *InstallError) Unwrap() error {
+	return e.Err
+}
+
+// Explanation describes the likely cause of the failure in plain words
+func (e *InstallError) Explanation() string {
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(e.Err, exec.ErrNotFound):
+		return fmt.Sprintf("%s is not installed or not in your PATH.", strings.Fields(e.Command)[0])
+	case e.Offline && containsAny(e.Output, cacheMissErrors):
+		return "You are offline and some packages are not in the local cache. Connect to the internet and try again."
+	case e.Offline:
+		return "You are offline, so packages could only come from the local cache, which could not satisfy the install. Connect to the internet and try again."
+	case containsAny(e.Output, networkErrors):
+		return "The package registry could not be reached. Check your internet connection, proxy and registry settings."
+	case errors.As(e.Err, &exitErr):
+		return fmt.Sprintf("%s exited with code %d. See its output above for details.", e.Command, exitErr.ExitCode())
+	default:
+		return fmt.Sprintf("%s could not be run: %v", e.Command, e.Err)
+	}
+}
+
+// InstallDependencies runs the package manager install in dir, streaming
+// its output to opts.Out. The install is stopped when ctx is cancelled.
+func InstallDependencies(ctx context.Context, dir, packageManager string, opts InstallOptions) error {
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+
+	args := []string{"install"}
+	var env []string
+	if opts.Offline {
+		var offline []string
+		offline, env = offlineInstall(dir, packageManager)
+		args = append(args, offline...)
+	}
+
+	tail := &tailBuffer{size: outputTailSize}
+	w := io.MultiWriter(out, tail)
+
+	err := util.RunCommandContextEnv(ctx, dir, env, w, w, packageManager, args...)
+	if err != nil {
+		return &InstallError{
+			Command: packageManager + " " + strings.Join(args, " "),
+			Output:  tail.String(),
+			Offline: opts.Offline,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// offlineInstall returns the install arguments and environment that make
+// packageManager install from its local cache
+func offlineInstall(dir, packageManager string) ([]string, []string) {
+	if packageManager == "yarn" && isYarnBerry(dir) {
+		return nil, offlineEnv
+	}
+	return offlineArgs[packageManager], nil
+}
+
+// isYarnBerry reports whether dir uses Yarn 2 or later. Yarn 1 is
+// assumed when the version cannot be determined.
+func isYarnBerry(dir string) bool {
+	version, err := yarnVersion(dir)
+	if err != nil {
+		return false
+	}
+	major, _, _ := strings.Cut(strings.TrimSpace(version), ".")
+	n, err := strconv.Atoi(major)
+	return err == nil && n >= 2
+}
+
+// containsAny reports whether s contains any of the fragments
+func containsAny(s string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if strings.Contains(s, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// tailBuffer keeps the last size bytes written to it
+type tailBuffer struct {
+	size int
+	data []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > b.size {
+		b.data = b.data[len(b.data)-b.size:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.data)
+}
@@ -0,0 +1,98 @@
+package install
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOfflineInstall(t *testing.T) {
+	tests := []struct {
+		packageManager string
+		yarnVersion    string
+		yarnErr        error
+		wantArgs       []string
+		wantEnv        []string
+	}{
+		{"npm", "", nil, []string{"--offline"}, nil},
+		{"pnpm", "", nil, []string{"--offline"}, nil},
+		{"bun", "", nil, []string{"--prefer-offline"}, nil},
+		{"yarn", "1.22.22\n", nil, []string{"--offline"}, nil},
+		{"yarn", "4.5.1\n", nil, nil, []string{"YARN_ENABLE_OFFLINE_MODE=1"}},
+		{"yarn", "2.4.3", nil, nil, []string{"YARN_ENABLE_OFFLINE_MODE=1"}},
+		{"yarn", "", exec.ErrNotFound, []string{"--offline"}, nil},
+	}
+
+	defer func(original func(string) (string, error)) { yarnVersion = original }(yarnVersion)
+	for _, tt := range tests {
+		t.Run(tt.packageManager+"@"+strings.TrimSpace(tt.yarnVersion), func(t *testing.T) {
+			yarnVersion = func(string) (string, error) { return tt.yarnVersion, tt.yarnErr }
+
+			args, env := offlineInstall(t.TempDir(), tt.packageManager)
+			if !slices.Equal(args, tt.wantArgs) || !slices.Equal(env, tt.wantEnv) {
+				t.Errorf("offlineInstall() = %v, %v, want %v, %v", args, env, tt.wantArgs, tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestInstallErrorExplanation(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+
+	tests := []struct {
+		name string
+		err  *InstallError
+		want string
+	}{
+		{
+			name: "not installed",
+			err:  &InstallError{Command: "pnpm install", Err: exec.ErrNotFound},
+			want: "pnpm is not installed",
+		},
+		{
+			name: "offline cache miss",
+			err:  &InstallError{Command: "npm install --offline", Offline: true, Output: "npm error code ENOTCACHED", Err: exitErr},
+			want: "some packages are not in the local cache",
+		},
+		{
+			name: "offline pnpm cache miss",
+			err:  &InstallError{Command: "pnpm install --offline", Offline: true, Output: "ERR_PNPM_NO_OFFLINE_TARBALL", Err: exitErr},
+			want: "some packages are not in the local cache",
+		},
+		{
+			name: "offline failure",
+			err:  &InstallError{Command: "npm install --offline", Offline: true, Output: "something else", Err: exitErr},
+			want: "could not satisfy the install",
+		},
+		{
+			name: "network error",
+			err:  &InstallError{Command: "npm install", Output: "npm error code ECONNREFUSED", Err: exitErr},
+			want: "The package registry could not be reached",
+		},
+		{
+			name: "dns failure",
+			err:  &InstallError{Command: "yarn install", Output: "getaddrinfo ENOTFOUND registry.yarnpkg.com", Err: exitErr},
+			want: "The package registry could not be reached",
+		},
+		{
+			name: "exit code",
+			err:  &InstallError{Command: "npm install", Output: "npm error peer dep conflict", Err: exitErr},
+			want: "npm install exited with code 3",
+		},
+		{
+			name: "other error",
+			err:  &InstallError{Command: "npm install", Err: errors.New("boom")},
+			want: "could not be run: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Explanation(); !strings.Contains(got, tt.want) {
+				t.Errorf("Explanation() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
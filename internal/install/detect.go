@@ -0,0 +1,116 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// DefaultPackageManager is used when no other package manager is detected
+const DefaultPackageManager = "npm"
+
+// Detection sources reported with a detected package manager
+const (
+	SourceUserAgent = "npm_config_user_agent"
+	SourceLockfile  = "lockfile"
+	SourcePath      = "PATH"
+	SourceDefault   = "default"
+)
+
+// lockfiles maps lockfile names to the package manager that writes them,
+// in the order they are checked
+var lockfiles = []struct {
+	name           string
+	packageManager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"bun.lock", "bun"},
+	{"bun.lockb", "bun"},
+	{"yarn.lock", "yarn"},
+	{"package-lock.json", "npm"},
+}
+
+// pathCandidates are the package managers looked up in PATH, in order
+var pathCandidates = []string{"pnpm", "bun", "yarn"}
+
+// Detection describes which package manager was detected and how
+type Detection struct {
+	PackageManager string
+	Source         string
+	Detail         string // e.g. the lockfile that was found
+}
+
+// IsPackageManager reports whether name is a supported package manager
+func IsPackageManager(name string) bool {
+	switch name {
+	case "npm", "pnpm", "yarn", "bun":
+		return true
+	}
+	return false
+}
+
+// DetectPackageManager infers the package manager for a project created
+// in dir. It checks npm_config_user_agent (set by npx, pnpm dlx, bunx and
+// yarn create), then lockfiles in dir and its parents, then PATH, and
+// falls back to npm.
+func DetectPackageManager(dir string) Detection {
+	if pm := FromUserAgent(os.Getenv("npm_config_user_agent")); pm != "" {
+		return Detection{PackageManager: pm, Source: SourceUserAgent}
+	}
+
+	if pm, lockfile := fromLockfile(dir); pm != "" {
+		return Detection{PackageManager: pm, Source: SourceLockfile, Detail: lockfile}
+	}
+
+	for _, pm := range pathCandidates {
+		if util.CommandExists(pm) {
+			return Detection{PackageManager: pm, Source: SourcePath}
+		}
+	}
+
+	return Detection{PackageManager: DefaultPackageManager, Source: SourceDefault}
+}
+
+// FromUserAgent returns the package manager named in an npm user agent
+// such as "pnpm/9.1.0 npm/? node/v20.11.0 linux x64"
+func FromUserAgent(userAgent string) string {
+	name, _, _ := strings.Cut(strings.TrimSpace(userAgent), "/")
+	if IsPackageManager(name) {
+		return name
+	}
+	return ""
+}
+
+// fromLockfile walks up from dir looking for a workspace lockfile
+func fromLockfile(dir string) (string, string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+
+	for {
+		for _, lockfile := range lockfiles {
+			path := filepath.Join(dir, lockfile.name)
+			if util.FileExists(path) {
+				return lockfile.packageManager, path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// RunScriptCommand returns the command that runs a package.json script
+// with packageManager, e.g. "npm run dev" or "pnpm dev"
+func RunScriptCommand(packageManager, script string) string {
+	if packageManager == "npm" || packageManager == "" {
+		return "npm run " + script
+	}
+	return packageManager + " " + script
+}
@@ -0,0 +1,111 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeCommands returns a PATH holding an executable for each name
+func fakeCommands(t *testing.T, names ...string) string {
+	t.Helper()
+	bin := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return bin
+}
+
+func TestFromUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"pnpm/9.1.0 npm/? node/v20.11.0 linux x64", "pnpm"},
+		{"yarn/1.22.22 npm/? node/v20.11.0 darwin arm64", "yarn"},
+		{"bun/1.1.8 npm/? node/v21.6.0 linux x64", "bun"},
+		{"npm/10.5.0 node/v20.11.0 win32 x64 workspaces/false", "npm"},
+		{"deno/1.44.0 npm/? deno/1.44.0 linux x86_64", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			if got := FromUserAgent(tt.userAgent); got != tt.want {
+				t.Errorf("FromUserAgent(%q) = %q, want %q", tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectPackageManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		lockfile  string // Created two directories above the project's parent
+		path      []string
+		want      Detection
+	}{
+		{
+			name:      "user agent",
+			userAgent: "pnpm/9.1.0 npm/? node/v20.11.0 linux x64",
+			path:      []string{"yarn"},
+			want:      Detection{PackageManager: "pnpm", Source: SourceUserAgent},
+		},
+		{
+			name:      "user agent over a conflicting lockfile",
+			userAgent: "bun/1.1.8 npm/? node/v21.6.0 linux x64",
+			lockfile:  "yarn.lock",
+			want:      Detection{PackageManager: "bun", Source: SourceUserAgent},
+		},
+		{
+			name:     "lockfile in a parent workspace",
+			lockfile: "pnpm-lock.yaml",
+			path:     []string{"yarn"},
+			want:     Detection{PackageManager: "pnpm", Source: SourceLockfile, Detail: "pnpm-lock.yaml"},
+		},
+		{
+			name:      "unknown user agent falls through to the lockfile",
+			userAgent: "deno/1.44.0 npm/? deno/1.44.0 linux x86_64",
+			lockfile:  "bun.lock",
+			want:      Detection{PackageManager: "bun", Source: SourceLockfile, Detail: "bun.lock"},
+		},
+		{
+			name: "PATH",
+			path: []string{"yarn", "bun"},
+			want: Detection{PackageManager: "bun", Source: SourcePath},
+		},
+		{
+			name: "npm fallback",
+			want: Detection{PackageManager: DefaultPackageManager, Source: SourceDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("npm_config_user_agent", tt.userAgent)
+			t.Setenv("PATH", fakeCommands(t, tt.path...))
+
+			workspace := t.TempDir()
+			dir := filepath.Join(workspace, "packages", "apps")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.lockfile != "" {
+				if err := os.WriteFile(filepath.Join(workspace, tt.lockfile), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want := tt.want
+			if want.Detail != "" {
+				want.Detail = filepath.Join(workspace, want.Detail)
+			}
+			if got := DetectPackageManager(dir); got != want {
+				t.Errorf("DetectPackageManager() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package install
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// defaultBranch is used when git's init.defaultBranch is unset
+const defaultBranch = "main"
+
+// initialCommitMessage is the message of the project's first commit
+const initialCommitMessage = "Initial commit from Create Next App"
+
+// GitSkippedError explains why git initialization was skipped
+type GitSkippedError struct {
+	Reason string
+}
+
+func (e *GitSkippedError) Error() string {
+	return "skipped git initialization: " + e.Reason
+}
+
+// InitGit initializes a git repository in dir and creates the initial
+// commit on the configured default branch. It returns a *GitSkippedError
+// when git is missing, dir already has a .git or is inside a work tree,
+// or no commit identity is configured. When the commit fails the .git
+// directory it created is removed again.
+func InitGit(ctx context.Context, dir string) error {
+	if !util.CommandExists("git") {
+		return &GitSkippedError{Reason: "git is not installed"}
+	}
+
+	// A .git left in the directory, even a broken one, is not ours to
+	// initialize or remove
+	if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+		return &GitSkippedError{Reason: "the project directory already has a .git"}
+	}
+
+	if _, err := runGit(ctx, dir, "rev-parse", "--is-inside-work-tree"); err == nil {
+		return &GitSkippedError{Reason: "the project is already inside a git work tree"}
+	}
+
+	for _, key := range []string{"user.name", "user.email"} {
+		if value, err := runGit(ctx, dir, "config", key); err != nil || value == "" {
+			return &GitSkippedError{Reason: fmt.Sprintf("git %s is not set", key)}
+		}
+	}
+
+	branch, _ := runGit(ctx, dir, "config", "init.defaultBranch")
+	if branch == "" {
+		branch = defaultBranch
+	}
+
+	if _, err := runGit(ctx, dir, "init", "--initial-branch="+branch); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"commit", "-m", initialCommitMessage},
+	} {
+		if _, err := runGit(ctx, dir, args...); err != nil {
+			if removeErr := os.RemoveAll(filepath.Join(dir, ".git")); removeErr != nil {
+				return errors.Join(err, removeErr)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := util.RunCommandContext(ctx, dir, &stdout, &stderr, "git", args...); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" && ctx.Err() == nil {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
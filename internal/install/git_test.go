@@ -0,0 +1,94 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// isolateGit points git at a global config with a commit identity and a
+// pre-commit hook that exits with hookStatus
+func isolateGit(t *testing.T, hookStatus int) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	home := t.TempDir()
+	hooks := filepath.Join(home, "hooks")
+	if err := os.Mkdir(hooks, 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook := fmt.Sprintf("#!/bin/sh\nexit %d\n", hookStatus)
+	if err := os.WriteFile(filepath.Join(hooks, "pre-commit"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[user]\n\tname = Test\n\temail = test@example.com\n[core]\n\thooksPath = " + filepath.ToSlash(hooks) + "\n"
+	if err := os.WriteFile(filepath.Join(home, "gitconfig"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(home))
+}
+
+// newProject returns a directory holding a single file
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestInitGit(t *testing.T) {
+	isolateGit(t, 0)
+	dir := newProject(t)
+
+	if err := InitGit(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(context.Background(), dir, "rev-parse", "HEAD"); err != nil {
+		t.Errorf("no initial commit: %v", err)
+	}
+}
+
+func TestInitGitRemovesItsRepositoryWhenCommitFails(t *testing.T) {
+	isolateGit(t, 1)
+	dir := newProject(t)
+
+	if err := InitGit(context.Background(), dir); err == nil {
+		t.Fatal("InitGit() succeeded with a failing pre-commit hook")
+	}
+	if _, err := os.Lstat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Errorf(".git was not removed: %v", err)
+	}
+}
+
+func TestInitGitKeepsExistingGitDirectory(t *testing.T) {
+	isolateGit(t, 1)
+	dir := newProject(t)
+	// A broken repository that rev-parse doesn't recognize
+	marker := filepath.Join(dir, ".git", "keep")
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := InitGit(context.Background(), dir)
+	var skipped *GitSkippedError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("err = %v, want a GitSkippedError", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("the existing .git was modified: %v", err)
+	}
+}
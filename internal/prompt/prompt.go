@@ -76,6 +76,30 @@ func AskSrcDir() (bool, error) {
 	return useSrcDir, err
 }
 
+// AskReactCompiler prompts for React Compiler preference
+func AskReactCompiler() (bool, error) {
+	var useReactCompiler bool
+
+	err := huh.NewConfirm().
+		Title("Would you like to use React Compiler?").
+		Value(&useReactCompiler).
+		Run()
+
+	return useReactCompiler, err
+}
+
+// AskCustomizeImportAlias prompts whether to customize the default import alias
+func AskCustomizeImportAlias() (bool, error) {
+	var customize bool
+
+	err := huh.NewConfirm().
+		Title("Would you like to customize the import alias (`@/*` by default)?").
+		Value(&customize).
+		Run()
+
+	return customize, err
+}
+
 // AskAppRouter is deprecated - App Router is now always enabled
 // Pages Router templates have been removed
 func AskAppRouter() (bool, error) {
@@ -83,14 +107,18 @@ func AskAppRouter() (bool, error) {
 	return true, nil
 }
 
-// AskImportAlias prompts for custom import alias
-func AskImportAlias() (string, error) {
+// AskImportAlias prompts for custom import alias, rejecting aliases that
+// shadow packages
+func AskImportAlias(packages []string) (string, error) {
 	var alias string
 
 	err := huh.NewInput().
 		Title("What import alias would you like configured?").
 		Value(&alias).
 		Placeholder("@/*").
+		Validate(func(alias string) error {
+			return ValidateImportAlias(alias, packages)
+		}).
 		Run()
 
 	if alias == "" {
@@ -1,6 +1,8 @@
 package prompt
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/huh"
 )
 
@@ -8,6 +10,7 @@ const (
 	SetupRecommended = "recommended"
 	SetupReuse       = "reuse"
 	SetupCustomize   = "customize"
+	SetupExample     = "example"
 )
 
 // AskSetupChoice prompts for initial setup choice
@@ -24,7 +27,8 @@ func AskSetupChoice(hasSavedPrefs bool) (string, error) {
 	}
 
 	options = append(options,
-		huh.NewOption("No, customize settings", SetupCustomize))
+		huh.NewOption("No, customize settings", SetupCustomize),
+		huh.NewOption("No, start from an official example", SetupExample))
 
 	err := huh.NewSelect[string]().
 		Title("Would you like to use the recommended Next.js defaults?").
@@ -34,3 +38,54 @@ func AskSetupChoice(hasSavedPrefs bool) (string, error) {
 
 	return choice, err
 }
+
+// ExampleOption is an example offered by AskExample
+type ExampleOption struct {
+	Name        string
+	Description string
+}
+
+// AskExample lets the user pick an example from a list narrowed down by
+// search as they type
+func AskExample(search func(term string) []ExampleOption) (string, error) {
+	var term, name string
+
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title("Search the official examples").
+			Placeholder("e.g. tailwind, auth, mdx").
+			Value(&term),
+		huh.NewSelect[string]().
+			Title("Which example would you like to use?").
+			OptionsFunc(func() []huh.Option[string] {
+				var options []huh.Option[string]
+				for _, example := range search(term) {
+					label := example.Name
+					if example.Description != "" {
+						label += " - " + truncate(example.Description, 60)
+					}
+					options = append(options, huh.NewOption(label, example.Name))
+				}
+				return options
+			}, &term).
+			Height(12).
+			Validate(func(name string) error {
+				if name == "" {
+					return fmt.Errorf("no example matches your search, try another term")
+				}
+				return nil
+			}).
+			Value(&name),
+	)).Run()
+
+	return name, err
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
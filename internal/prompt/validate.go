@@ -25,8 +25,9 @@ func ValidateProjectName(name string) error {
 	return nil
 }
 
-// ValidateImportAlias validates import alias format
-func ValidateImportAlias(alias string) error {
+// ValidateImportAlias validates import alias format. The alias can't
+// shadow packages, the dependencies of the generated project.
+func ValidateImportAlias(alias string, packages []string) error {
 	if alias == "" {
 		return nil
 	}
@@ -35,5 +36,35 @@ func ValidateImportAlias(alias string) error {
 		return fmt.Errorf("import alias must end with '/*'")
 	}
 
+	prefix := strings.TrimSuffix(alias, "/*")
+	if prefix == "" {
+		return fmt.Errorf("import alias must have a prefix before '/*'")
+	}
+
+	if strings.ContainsAny(prefix, "*\"' \t") {
+		return fmt.Errorf("import alias can only contain a single '*' and no quotes or spaces")
+	}
+
+	// "@/*" is safe because npm scopes cannot be empty
+	if strings.HasPrefix(prefix, "@") && prefix != "@" {
+		return fmt.Errorf("import alias %q collides with npm scoped packages, use a prefix like '@/*' or '~/*'", alias)
+	}
+
+	// Node.js reserves "#" and "#/" in subpath imports
+	if prefix == "#" || strings.HasPrefix(prefix, "#/") {
+		return fmt.Errorf("import alias %q collides with Node.js subpath imports, use a name like '#app/*'", alias)
+	}
+
+	if strings.HasPrefix(prefix, "node:") || util.IsBuiltinModule(prefix) {
+		return fmt.Errorf("import alias %q collides with a Node.js core module", alias)
+	}
+
+	// The generated files import these packages, e.g. "next/image"
+	for _, pkg := range packages {
+		if prefix == pkg || strings.HasPrefix(prefix, pkg+"/") {
+			return fmt.Errorf("import alias %q collides with the %s package, use a prefix like '@/*' or '~/*'", alias, pkg)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,43 @@
+package prompt
+
+import "testing"
+
+func TestValidateImportAlias(t *testing.T) {
+	packages := []string{"@types/react", "next", "react", "react-dom"}
+
+	tests := []struct {
+		alias string
+		valid bool
+	}{
+		{"", true},
+		{"@/*", true},
+		{"~/*", true},
+		{"#app/*", true},
+		{"nextjs/*", true},
+		{"@scope/*", false},
+		{"@types/*", false},
+		{"#/*", false},
+		{"#*", false},
+		{"node:*", false},
+		{"node:fs/*", false},
+		{"fs/*", false},
+		{"path/*", false},
+		{"next/*", false},
+		{"next/font/*", false},
+		{"react/*", false},
+		{"react-dom/*", false},
+		{"@", false},
+		{"~", false},
+		{"/*", false},
+		{"my app/*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := ValidateImportAlias(tt.alias, packages)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateImportAlias(%q) = %v, want valid %v", tt.alias, err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// renames maps template file names to their names in the generated project
+var renames = map[string]string{
+	"gitignore":          ".gitignore",
+	"README-template.md": "README.md",
+}
+
+// File is a generated project file held in memory
+type File struct {
+	Path string // Slash-separated, relative to the project root
+	Data []byte
+	Mode fs.FileMode
+}
+
+// Project is the set of files rendered for a new project
+type Project struct {
+	files map[string]*File
+}
+
+// NewProject creates an empty Project
+func NewProject() *Project {
+	return &Project{files: map[string]*File{}}
+}
+
+// Add stores a file, replacing any file at the same path
+func (p *Project) Add(name string, data []byte, mode fs.FileMode) {
+	name = path.Clean(name)
+	p.files[name] = &File{Path: name, Data: data, Mode: mode}
+}
+
+// Get returns the file at name, or nil if it does not exist
+func (p *Project) Get(name string) *File {
+	return p.files[path.Clean(name)]
+}
+
+// Remove deletes the file at name
+func (p *Project) Remove(name string) {
+	delete(p.files, path.Clean(name))
+}
+
+// Rename moves the file at oldName to newName
+func (p *Project) Rename(oldName, newName string) {
+	file := p.Get(oldName)
+	if file == nil {
+		return
+	}
+	p.Remove(oldName)
+	p.Add(newName, file.Data, file.Mode)
+}
+
+// Files returns every file sorted by path
+func (p *Project) Files() []*File {
+	files := make([]*File, 0, len(p.files))
+	for _, file := range p.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// loadTemplate reads a template tree into a Project, applying renames
+func loadTemplate(templates fs.FS, root string) (*Project, error) {
+	project := NewProject()
+
+	err := fs.WalkDir(templates, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel := p[len(root)+1:]
+		if renamed, ok := renames[path.Base(rel)]; ok {
+			rel = path.Join(path.Dir(rel), renamed)
+		}
+
+		data, err := fs.ReadFile(templates, p)
+		if err != nil {
+			return err
+		}
+		project.Add(rel, data, 0644)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", root, err)
+	}
+
+	return project, nil
+}
+
+// Write writes every file of the project below the journal's root,
+// recording each file and directory it creates
+func Write(project *Project, journal *util.Journal) error {
+	for _, file := range project.Files() {
+		dst := filepath.Join(journal.Root(), filepath.FromSlash(file.Path))
+		if err := journal.WriteFile(dst, file.Data, file.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
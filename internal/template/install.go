@@ -0,0 +1,82 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/util"
+)
+
+// transforms are applied in order to every rendered template
+var transforms = []func(*Project, *config.Config) error{
+	applySrcDir,
+	applyImportAlias,
+	applyLinter,
+	applyBundler,
+	applyReactCompiler,
+	applyReadme,
+}
+
+// GetTemplateName returns the template directory for a configuration
+func GetTemplateName(cfg *config.Config) string {
+	if cfg.APIOnly {
+		return "app-api"
+	}
+
+	name := "app"
+	if cfg.Tailwind {
+		name += "-tw"
+	}
+	if cfg.EmptyTemplate {
+		name += "-empty"
+	}
+	return name
+}
+
+// GetTemplateMode returns the language variant of a template
+func GetTemplateMode(cfg *config.Config) string {
+	if cfg.TypeScript {
+		return "ts"
+	}
+	return "js"
+}
+
+// Render builds the project for a configuration in memory
+func Render(templates fs.FS, cfg *config.Config) (*Project, error) {
+	root := path.Join("templates", GetTemplateName(cfg), GetTemplateMode(cfg))
+
+	project, err := loadTemplate(templates, root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transform := range transforms {
+		if err := transform(project, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := NewPackageJSON(cfg).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	project.Add("package.json", data, 0644)
+
+	return project, nil
+}
+
+// Install renders the selected embedded template and writes it into the
+// journal's root, normally cfg.ProjectPath. It returns the written project.
+func Install(templates fs.FS, cfg *config.Config, journal *util.Journal) (*Project, error) {
+	project, err := Render(templates, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Write(project, journal); err != nil {
+		return nil, fmt.Errorf("failed to write template: %w", err)
+	}
+	return project, nil
+}
@@ -0,0 +1,113 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+// nextConfigFiles are the Next.js config files shipped by the templates
+var nextConfigFiles = []string{"next.config.ts", "next.config.mjs"}
+
+// nextConfigPlaceholder is the comment the template config object holds
+const nextConfigPlaceholder = "/* config options here */"
+
+// nextConfigExport is the default export every template config ends with
+const nextConfigExport = "export default nextConfig;"
+
+// getNextConfig returns the project's Next.js config file
+func getNextConfig(project *Project) (*File, error) {
+	for _, name := range nextConfigFiles {
+		if file := project.Get(name); file != nil {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("template has no next.config file")
+}
+
+// applyBundler wraps the Next.js config with the Rspack integration.
+// Webpack and Turbopack only differ in the package.json scripts.
+func applyBundler(project *Project, cfg *config.Config) error {
+	if cfg.Bundler != "rspack" {
+		return nil
+	}
+
+	file, err := getNextConfig(project)
+	if err != nil {
+		return err
+	}
+
+	content := string(file.Data)
+	if !strings.Contains(content, nextConfigExport) {
+		return fmt.Errorf("%s has no %q to wrap with Rspack", file.Path, nextConfigExport)
+	}
+
+	content = addImport(content, `import withRspack from "next-rspack";`)
+	content = strings.Replace(content, nextConfigExport, "export default withRspack(nextConfig);", 1)
+	file.Data = []byte(content)
+
+	return nil
+}
+
+// applyReactCompiler enables the React Compiler in the Next.js config
+func applyReactCompiler(project *Project, cfg *config.Config) error {
+	if !cfg.ReactCompiler || cfg.APIOnly {
+		return nil
+	}
+
+	file, err := getNextConfig(project)
+	if err != nil {
+		return err
+	}
+
+	content, err := addConfigOption(string(file.Data), "reactCompiler: true,")
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.Path, err)
+	}
+	file.Data = []byte(content)
+
+	return nil
+}
+
+// addConfigOption adds an option to the nextConfig object, replacing the
+// template placeholder and keeping options added before it
+func addConfigOption(content, option string) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "const nextConfig") || !strings.HasSuffix(strings.TrimSpace(line), "{") {
+			continue
+		}
+
+		// Drop the placeholder once the object holds a real option
+		if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == nextConfigPlaceholder {
+			lines = append(lines[:i+1], lines[i+2:]...)
+		}
+
+		added := append([]string{}, lines[:i+1]...)
+		added = append(added, "  "+option+"\n")
+		added = append(added, lines[i+1:]...)
+		return strings.Join(added, ""), nil
+	}
+
+	return "", fmt.Errorf("no nextConfig object to add %q to", option)
+}
+
+// addImport inserts an import after the last import of a module, or at
+// the top when it has none
+func addImport(content, line string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	last := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "import ") {
+			last = i
+		}
+	}
+
+	if last == -1 {
+		return line + "\n\n" + content
+	}
+	return strings.Join(lines[:last+1], "") + line + "\n" + strings.Join(lines[last+1:], "")
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"os"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+// templatesFS holds the templates directory at the module root
+var templatesFS = os.DirFS("../..")
+
+func TestNextConfigBundlers(t *testing.T) {
+	const (
+		tsImport   = "import type { NextConfig } from \"next\";\n"
+		jsType     = "/** @type {import('next').NextConfig} */\n"
+		rspack     = "import withRspack from \"next-rspack\";\n"
+		tsConfig   = "const nextConfig: NextConfig = {\n"
+		jsConfig   = "const nextConfig = {\n"
+		options    = "  /* config options here */\n};\n\n"
+		compiler   = "  reactCompiler: true,\n};\n\n"
+		export     = "export default nextConfig;\n"
+		withRspack = "export default withRspack(nextConfig);\n"
+	)
+
+	tests := []struct {
+		bundler       string
+		typeScript    bool
+		reactCompiler bool
+		file          string
+		want          string
+	}{
+		{"turbopack", true, false, "next.config.ts", tsImport + "\n" + tsConfig + options + export},
+		{"turbopack", true, true, "next.config.ts", tsImport + "\n" + tsConfig + compiler + export},
+		{"turbopack", false, false, "next.config.mjs", jsType + jsConfig + options + export},
+		{"turbopack", false, true, "next.config.mjs", jsType + jsConfig + compiler + export},
+		{"webpack", true, false, "next.config.ts", tsImport + "\n" + tsConfig + options + export},
+		{"webpack", true, true, "next.config.ts", tsImport + "\n" + tsConfig + compiler + export},
+		{"webpack", false, false, "next.config.mjs", jsType + jsConfig + options + export},
+		{"webpack", false, true, "next.config.mjs", jsType + jsConfig + compiler + export},
+		{"rspack", true, false, "next.config.ts", tsImport + rspack + "\n" + tsConfig + options + withRspack},
+		{"rspack", true, true, "next.config.ts", tsImport + rspack + "\n" + tsConfig + compiler + withRspack},
+		{"rspack", false, false, "next.config.mjs", rspack + "\n" + jsType + jsConfig + options + withRspack},
+		{"rspack", false, true, "next.config.mjs", rspack + "\n" + jsType + jsConfig + compiler + withRspack},
+	}
+
+	for _, tt := range tests {
+		name := tt.bundler + "/" + tt.file
+		if tt.reactCompiler {
+			name += "/react-compiler"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Bundler = tt.bundler
+			cfg.TypeScript = tt.typeScript
+			cfg.ReactCompiler = tt.reactCompiler
+
+			project, err := Render(templatesFS, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := project.Get(tt.file)
+			if file == nil {
+				t.Fatalf("no %s in the project", tt.file)
+			}
+			if got := string(file.Data); got != tt.want {
+				t.Errorf("%s =\n%s\nwant\n%s", tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package template
+
+import (
+	"encoding/json"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+// PackageJSON is the package.json written to a new project
+type PackageJSON struct {
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Private         bool              `json:"private"`
+	Scripts         Scripts           `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies,omitempty"`
+}
+
+// Scripts are the npm scripts of a generated project, in the order
+// create-next-app writes them
+type Scripts struct {
+	Dev    string `json:"dev"`
+	Build  string `json:"build"`
+	Start  string `json:"start"`
+	Lint   string `json:"lint,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// NewPackageJSON builds the package.json for a configuration
+func NewPackageJSON(cfg *config.Config) *PackageJSON {
+	pkg := &PackageJSON{
+		Name:            cfg.ProjectName,
+		Version:         "0.1.0",
+		Private:         true,
+		Scripts:         newScripts(cfg),
+		Dependencies:    map[string]string{},
+		DevDependencies: map[string]string{},
+	}
+
+	addVersions(pkg.Dependencies, "next", "react", "react-dom")
+	if cfg.Bundler == "rspack" {
+		addVersions(pkg.Dependencies, "next-rspack")
+	}
+
+	if cfg.TypeScript {
+		addVersions(pkg.DevDependencies, "typescript", "@types/node")
+		if !cfg.APIOnly {
+			addVersions(pkg.DevDependencies, "@types/react", "@types/react-dom")
+		}
+	}
+
+	if cfg.Tailwind && !cfg.APIOnly {
+		addVersions(pkg.DevDependencies, "tailwindcss", "@tailwindcss/postcss")
+	}
+
+	switch cfg.Linter {
+	case "eslint":
+		addVersions(pkg.DevDependencies, "eslint", "eslint-config-next")
+	case "biome":
+		addVersions(pkg.DevDependencies, "@biomejs/biome")
+	}
+
+	if cfg.ReactCompiler && !cfg.APIOnly {
+		addVersions(pkg.DevDependencies, "babel-plugin-react-compiler")
+	}
+
+	return pkg
+}
+
+// newScripts returns the scripts for the configured bundler and linter
+func newScripts(cfg *config.Config) Scripts {
+	var bundlerFlag string
+	switch cfg.Bundler {
+	case "turbopack":
+		bundlerFlag = " --turbopack"
+	case "webpack", "rspack":
+		bundlerFlag = " --webpack"
+	}
+
+	scripts := Scripts{
+		Dev:   "next dev" + bundlerFlag,
+		Build: "next build" + bundlerFlag,
+		Start: "next start",
+	}
+
+	switch cfg.Linter {
+	case "eslint":
+		scripts.Lint = "eslint"
+	case "biome":
+		scripts.Lint = "biome check"
+		scripts.Format = "biome format --write"
+	}
+
+	return scripts
+}
+
+// addVersions adds the pinned version of each package to deps
+func addVersions(deps map[string]string, packages ...string) {
+	for _, name := range packages {
+		deps[name] = Versions[name]
+	}
+}
+
+// Marshal encodes the package.json with a trailing newline
+func (p *PackageJSON) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
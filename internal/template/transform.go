@@ -0,0 +1,179 @@
+package template
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+	"github.com/yeasin2002/better-next-app/internal/install"
+)
+
+// srcDirs are the top-level directories moved under src/ with SrcDir
+var srcDirs = []string{"app", "pages", "styles", "components", "lib"}
+
+// pathConfigs are the files holding the compilerOptions.paths mapping
+var pathConfigs = []string{"tsconfig.json", "jsconfig.json"}
+
+// sourceExts are the file types whose imports use the import alias
+var sourceExts = map[string]bool{
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+	".css": true,
+}
+
+// defaultImportAlias is the alias every template is written with
+const defaultImportAlias = "@/*"
+
+// Linter config files shipped by the templates
+const (
+	eslintConfigFile = "eslint.config.mjs"
+	biomeConfigFile  = "biome.json"
+)
+
+// pathsEntry matches the first entry of the compilerOptions.paths object
+var pathsEntry = regexp.MustCompile(`("paths"\s*:\s*\{\s*)"([^"]*)"(\s*:\s*\[\s*)"([^"]*)"`)
+
+// applySrcDir moves the source directories under src/ and points the
+// configs referencing them at the new location
+func applySrcDir(project *Project, cfg *config.Config) error {
+	if !cfg.SrcDir {
+		return nil
+	}
+
+	for _, file := range project.Files() {
+		top, _, _ := strings.Cut(file.Path, "/")
+		for _, dir := range srcDirs {
+			if top == dir {
+				project.Rename(file.Path, "src/"+file.Path)
+				break
+			}
+		}
+	}
+
+	for _, name := range pathConfigs {
+		if file := project.Get(name); file != nil {
+			file.Data = rewritePaths(file.Data, func(alias, target string) (string, string) {
+				return alias, "./src/" + strings.TrimPrefix(target, "./")
+			})
+		}
+	}
+
+	// The README points at the page to edit
+	if file := project.Get("README.md"); file != nil {
+		file.Data = []byte(strings.ReplaceAll(string(file.Data), "`app/", "`src/app/"))
+	}
+
+	return nil
+}
+
+// applyImportAlias replaces the default import alias with cfg.ImportAlias
+// in the path configs and every source file
+func applyImportAlias(project *Project, cfg *config.Config) error {
+	if cfg.ImportAlias == "" || cfg.ImportAlias == defaultImportAlias {
+		return nil
+	}
+
+	for _, name := range pathConfigs {
+		if file := project.Get(name); file != nil {
+			file.Data = rewritePaths(file.Data, func(alias, target string) (string, string) {
+				if alias == defaultImportAlias {
+					alias = cfg.ImportAlias
+				}
+				return alias, target
+			})
+		}
+	}
+
+	oldPrefix := strings.TrimSuffix(defaultImportAlias, "*")
+	newPrefix := strings.TrimSuffix(cfg.ImportAlias, "*")
+	replacer := strings.NewReplacer(
+		`"`+oldPrefix, `"`+newPrefix,
+		`'`+oldPrefix, `'`+newPrefix,
+		"`"+oldPrefix, "`"+newPrefix,
+	)
+
+	for _, file := range project.Files() {
+		if !sourceExts[path.Ext(file.Path)] {
+			continue
+		}
+		file.Data = []byte(replacer.Replace(string(file.Data)))
+	}
+
+	return nil
+}
+
+// applyLinter keeps only the config of the selected linter, generating
+// the ESLint config for templates that do not ship one
+func applyLinter(project *Project, cfg *config.Config) error {
+	switch cfg.Linter {
+	case "eslint":
+		project.Remove(biomeConfigFile)
+		if project.Get(eslintConfigFile) == nil {
+			project.Add(eslintConfigFile, newESLintConfig(cfg), 0644)
+		}
+	case "biome":
+		project.Remove(eslintConfigFile)
+	default:
+		project.Remove(eslintConfigFile)
+		project.Remove(biomeConfigFile)
+	}
+	return nil
+}
+
+// newESLintConfig returns the flat ESLint config with the Next.js presets
+// for the configured language
+func newESLintConfig(cfg *config.Config) []byte {
+	var b strings.Builder
+
+	b.WriteString("import { defineConfig, globalIgnores } from \"eslint/config\";\n")
+	b.WriteString("import nextVitals from \"eslint-config-next/core-web-vitals\";\n")
+	if cfg.TypeScript {
+		b.WriteString("import nextTs from \"eslint-config-next/typescript\";\n")
+	}
+	b.WriteString("\nconst eslintConfig = defineConfig([\n  ...nextVitals,\n")
+	if cfg.TypeScript {
+		b.WriteString("  ...nextTs,\n")
+	}
+	b.WriteString(`  // Override default ignores of eslint-config-next.
+  globalIgnores([
+    // Default ignores of eslint-config-next:
+    ".next/**",
+    "out/**",
+    "build/**",
+    "next-env.d.ts",
+  ]),
+]);
+
+export default eslintConfig;
+`)
+
+	return []byte(b.String())
+}
+
+// readmeDevCommands is the README block listing the dev command of every
+// package manager
+const readmeDevCommands = "npm run dev\n# or\nyarn dev\n# or\npnpm dev\n# or\nbun dev\n"
+
+// applyReadme shows only the selected package manager's dev command in
+// the README
+func applyReadme(project *Project, cfg *config.Config) error {
+	file := project.Get("README.md")
+	if file == nil || cfg.PackageManager == "" {
+		return nil
+	}
+
+	command := install.RunScriptCommand(cfg.PackageManager, "dev") + "\n"
+	file.Data = []byte(strings.Replace(string(file.Data), readmeDevCommands, command, 1))
+	return nil
+}
+
+// rewritePaths rewrites the alias and target of the compilerOptions.paths
+// entry while keeping the rest of the file untouched
+func rewritePaths(data []byte, rewrite func(alias, target string) (string, string)) []byte {
+	return pathsEntry.ReplaceAllFunc(data, func(match []byte) []byte {
+		parts := pathsEntry.FindSubmatch(match)
+		alias, target := rewrite(string(parts[2]), string(parts[4]))
+		return []byte(string(parts[1]) + `"` + alias + `"` + string(parts[3]) + `"` + target + `"`)
+	})
+}
@@ -0,0 +1,198 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yeasin2002/better-next-app/internal/config"
+)
+
+func TestApplyImportAlias(t *testing.T) {
+	project := NewProject()
+	project.Add("tsconfig.json", []byte(`{"compilerOptions": {"paths": {"@/*": ["./*"]}}}`), 0644)
+	project.Add("app/page.tsx", []byte("import Button from \"@/components/button\";\nimport { cn } from '@/lib/utils';\nconst icon = `@/public/icon.svg`;\nimport \"@scope/pkg\";\n"), 0644)
+	project.Add("app/globals.css", []byte("@import \"@/styles/base.css\";\n"), 0644)
+	project.Add("README.md", []byte("Edit `@/app/page.tsx`.\n"), 0644)
+
+	cfg := config.DefaultConfig()
+	cfg.ImportAlias = "~/*"
+	if err := applyImportAlias(project, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"tsconfig.json":   `{"compilerOptions": {"paths": {"~/*": ["./*"]}}}`,
+		"app/page.tsx":    "import Button from \"~/components/button\";\nimport { cn } from '~/lib/utils';\nconst icon = `~/public/icon.svg`;\nimport \"@scope/pkg\";\n",
+		"app/globals.css": "@import \"~/styles/base.css\";\n",
+		"README.md":       "Edit `@/app/page.tsx`.\n", // Not a source file
+	}
+	for name, content := range want {
+		if got := string(project.Get(name).Data); got != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestRenderImportAlias(t *testing.T) {
+	for _, typeScript := range []bool{true, false} {
+		cfg := config.DefaultConfig()
+		cfg.TypeScript = typeScript
+		cfg.ImportAlias = "~/*"
+
+		project, err := Render(templatesFS, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := "jsconfig.json"
+		if typeScript {
+			name = "tsconfig.json"
+		}
+		data := string(project.Get(name).Data)
+		if !strings.Contains(data, `"~/*": ["./*"]`) || strings.Contains(data, `"@/*"`) {
+			t.Errorf("%s paths were not rewritten:\n%s", name, data)
+		}
+	}
+}
+
+func TestRenderSrcDir(t *testing.T) {
+	tests := []struct {
+		apiOnly    bool
+		typeScript bool
+		entry      string
+		pathConfig string
+	}{
+		{false, true, "app/page.tsx", "tsconfig.json"},
+		{false, false, "app/page.js", "jsconfig.json"},
+		{true, true, "app/route.ts", "tsconfig.json"},
+		{true, false, "app/route.js", "jsconfig.json"},
+	}
+
+	for _, tt := range tests {
+		for _, alias := range []string{"@/*", "~/*"} {
+			t.Run(tt.entry+"/"+alias, func(t *testing.T) {
+				cfg := config.DefaultConfig()
+				cfg.APIOnly = tt.apiOnly
+				cfg.TypeScript = tt.typeScript
+				cfg.ImportAlias = alias
+				cfg.SrcDir = true
+
+				project, err := Render(templatesFS, cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if project.Get("src/"+tt.entry) == nil {
+					t.Errorf("src/%s is missing", tt.entry)
+				}
+				for _, file := range project.Files() {
+					if strings.HasPrefix(file.Path, "app/") {
+						t.Errorf("%s was not moved under src/", file.Path)
+					}
+				}
+				if !tt.apiOnly && project.Get("public/next.svg") == nil {
+					t.Error("public/ was moved under src/")
+				}
+
+				paths := string(project.Get(tt.pathConfig).Data)
+				if want := `"` + alias + `": ["./src/*"]`; !strings.Contains(paths, want) {
+					t.Errorf("%s paths don't contain %s:\n%s", tt.pathConfig, want, paths)
+				}
+
+				if readme := project.Get("README.md"); readme != nil {
+					data := string(readme.Data)
+					if !strings.Contains(data, "`src/app/") || strings.Contains(data, " `app/") {
+						t.Errorf("README.md doesn't point under src/app:\n%s", data)
+					}
+					if !tt.apiOnly && !strings.Contains(data, "`src/"+tt.entry+"`") {
+						t.Errorf("README.md doesn't point at src/%s", tt.entry)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenderLinter(t *testing.T) {
+	tests := []struct {
+		linter     string
+		eslint     bool
+		biome      bool
+		scripts    Scripts
+		devDeps    []string
+		notDevDeps []string
+	}{
+		{
+			linter:     "eslint",
+			eslint:     true,
+			scripts:    Scripts{Lint: "eslint"},
+			devDeps:    []string{"eslint", "eslint-config-next"},
+			notDevDeps: []string{"@biomejs/biome"},
+		},
+		{
+			linter:     "biome",
+			biome:      true,
+			scripts:    Scripts{Lint: "biome check", Format: "biome format --write"},
+			devDeps:    []string{"@biomejs/biome"},
+			notDevDeps: []string{"eslint", "eslint-config-next"},
+		},
+		{
+			linter:     "none",
+			notDevDeps: []string{"eslint", "eslint-config-next", "@biomejs/biome"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, apiOnly := range []bool{false, true} {
+			for _, typeScript := range []bool{true, false} {
+				name := tt.linter + "/" + GetTemplateMode(&config.Config{TypeScript: typeScript})
+				if apiOnly {
+					name += "/app-api"
+				}
+				t.Run(name, func(t *testing.T) {
+					cfg := config.DefaultConfig()
+					cfg.Linter = tt.linter
+					cfg.APIOnly = apiOnly
+					cfg.TypeScript = typeScript
+
+					project, err := Render(templatesFS, cfg)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					eslintConfig := project.Get(eslintConfigFile)
+					if got := eslintConfig != nil; got != tt.eslint {
+						t.Errorf("%s exists = %v, want %v", eslintConfigFile, got, tt.eslint)
+					}
+					if got := project.Get(biomeConfigFile) != nil; got != tt.biome {
+						t.Errorf("%s exists = %v, want %v", biomeConfigFile, got, tt.biome)
+					}
+					if eslintConfig != nil {
+						data := string(eslintConfig.Data)
+						if !strings.Contains(data, `"eslint-config-next/core-web-vitals"`) {
+							t.Errorf("%s doesn't import the core web vitals preset:\n%s", eslintConfigFile, data)
+						}
+						if got := strings.Contains(data, `"eslint-config-next/typescript"`); got != typeScript {
+							t.Errorf("%s imports the TypeScript preset = %v, want %v", eslintConfigFile, got, typeScript)
+						}
+					}
+
+					pkg := NewPackageJSON(cfg)
+					if pkg.Scripts.Lint != tt.scripts.Lint || pkg.Scripts.Format != tt.scripts.Format {
+						t.Errorf("lint, format scripts = %q, %q, want %q, %q",
+							pkg.Scripts.Lint, pkg.Scripts.Format, tt.scripts.Lint, tt.scripts.Format)
+					}
+					for _, dep := range tt.devDeps {
+						if pkg.DevDependencies[dep] == "" {
+							t.Errorf("devDependencies are missing %s", dep)
+						}
+					}
+					for _, dep := range tt.notDevDeps {
+						if _, ok := pkg.DevDependencies[dep]; ok {
+							t.Errorf("devDependencies include %s", dep)
+						}
+					}
+				})
+			}
+		}
+	}
+}
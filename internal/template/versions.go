@@ -0,0 +1,42 @@
+package template
+
+import (
+	"maps"
+	"slices"
+)
+
+// Versions pins the exact package versions used by generated projects so
+// every project created by a given release starts from the same
+// dependencies. Ranges don't belong here.
+var Versions = map[string]string{
+	// Framework
+	"next":      "16.0.1",
+	"react":     "19.2.0",
+	"react-dom": "19.2.0",
+
+	// TypeScript
+	"typescript":       "5.9.3",
+	"@types/node":      "20.19.0",
+	"@types/react":     "19.2.2",
+	"@types/react-dom": "19.2.2",
+
+	// Styling
+	"tailwindcss":          "4.1.16",
+	"@tailwindcss/postcss": "4.1.16",
+
+	// Linting & Formatting
+	"eslint":             "9.38.0",
+	"eslint-config-next": "16.0.1",
+	"@biomejs/biome":     "2.2.0",
+
+	// Bundler
+	"next-rspack": "16.0.1",
+
+	// Features
+	"babel-plugin-react-compiler": "1.0.0",
+}
+
+// PackageNames returns the names of every package in Versions, sorted
+func PackageNames() []string {
+	return slices.Sorted(maps.Keys(Versions))
+}
@@ -2,9 +2,17 @@ package util
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"os"
 	"os/exec"
+	"time"
 )
 
+// killGracePeriod is how long a cancelled command and the processes it
+// started may take to exit before they are killed forcefully
+var killGracePeriod = 5 * time.Second
+
 // RunCommand executes a command and returns output
 func RunCommand(name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
@@ -34,6 +42,54 @@ func RunCommandInDir(dir, name string, args ...string) (string, error) {
 	return stdout.String(), nil
 }
 
+// RunCommandContext executes a command in dir, streaming its output to
+// stdout and stderr as it runs. When ctx is cancelled the command's whole
+// process group is terminated, and killed once killGracePeriod has passed,
+// so package managers don't leave orphaned children behind.
+func RunCommandContext(ctx context.Context, dir string, stdout, stderr io.Writer, name string, args ...string) error {
+	return RunCommandContextEnv(ctx, dir, nil, stdout, stderr, name, args...)
+}
+
+// RunCommandContextEnv is RunCommandContext with env added to the
+// environment of the command
+func RunCommandContextEnv(ctx context.Context, dir string, env []string, stdout, stderr io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = killGracePeriod
+
+	// Go only kills the command itself after WaitDelay, the processes it
+	// started are killed once the same grace period has passed
+	var killDeadline time.Time
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		killDeadline = time.Now().Add(killGracePeriod)
+		return terminateProcessGroup(cmd)
+	}
+
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if !killDeadline.IsZero() {
+			reapProcessGroup(cmd.Process.Pid, killDeadline)
+		}
+		return ctxErr
+	}
+	return err
+}
+
+// reapProcessGroup waits until deadline for the processes left in the
+// group pgid to exit, then kills the remaining ones
+func reapProcessGroup(pgid int, deadline time.Time) {
+	for processGroupAlive(pgid) && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	forceKillProcessGroup(pgid)
+}
+
 // CommandExists checks if a command is available in PATH
 func CommandExists(name string) bool {
 	_, err := exec.LookPath(name)
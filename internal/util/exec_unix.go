@@ -0,0 +1,29 @@
+//go:build !windows
+
+package util
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcessGroup asks the command and every process it started
+// to exit
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// processGroupAlive reports whether any process of the group is left
+func processGroupAlive(pgid int) bool {
+	return syscall.Kill(-pgid, 0) == nil
+}
+
+// forceKillProcessGroup kills every process left in the group
+func forceKillProcessGroup(pgid int) {
+	syscall.Kill(-pgid, syscall.SIGKILL)
+}
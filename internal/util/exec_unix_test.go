@@ -0,0 +1,77 @@
+//go:build !windows
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// processRunning reports whether pid is a live process, not a zombie
+// waiting to be reaped
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return true
+	}
+	// The state follows the command name in parentheses
+	_, state, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(state, "Z")
+}
+
+func TestRunCommandContextKillsGrandchildren(t *testing.T) {
+	defer func(original time.Duration) { killGracePeriod = original }(killGracePeriod)
+	killGracePeriod = 200 * time.Millisecond
+
+	// The grandchild ignores SIGTERM, like a stuck postinstall script
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	script := `(trap "" TERM; exec sleep 100) & echo $! > "$1.tmp" && mv "$1.tmp" "$1"; wait`
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- RunCommandContext(ctx, t.TempDir(), io.Discard, io.Discard, "sh", "-c", script, "sh", pidFile)
+	}()
+
+	var pid int
+	for deadline := time.Now().Add(5 * time.Second); pid == 0; {
+		if data, err := os.ReadFile(pidFile); err == nil {
+			if pid, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the grandchild never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer syscall.Kill(pid, syscall.SIGKILL)
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the cancelled command didn't return")
+	}
+
+	for deadline := time.Now().Add(2 * time.Second); processRunning(pid); {
+		if time.Now().After(deadline) {
+			t.Fatalf("the grandchild %d is still running", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package util
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminateProcessGroup kills the command and every process it started
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// processGroupAlive always reports false, taskkill already killed the
+// whole tree
+func processGroupAlive(int) bool {
+	return false
+}
+
+// forceKillProcessGroup does nothing, taskkill already killed the whole
+// tree
+func forceKillProcessGroup(int) {}
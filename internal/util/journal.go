@@ -0,0 +1,184 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Journal records what project creation adds to a directory so a failed
+// or interrupted run can remove exactly that and nothing else
+type Journal struct {
+	root        string
+	rootCreated bool
+	known       map[string]bool
+	created     []string
+	backups     []backup
+}
+
+// backup is the original content of a file that was overwritten
+type backup struct {
+	path string
+	data []byte
+	mode fs.FileMode
+}
+
+// NewJournal starts a journal for root, remembering the entries it
+// already contains
+func NewJournal(root string) (*Journal, error) {
+	j := &Journal{root: root, known: map[string]bool{}}
+
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		j.rootCreated = true
+		return j, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		j.known[entry.Name()] = true
+	}
+	return j, nil
+}
+
+// Root returns the directory the journal tracks
+func (j *Journal) Root() string {
+	return j.root
+}
+
+// Created returns the recorded paths in creation order
+func (j *Journal) Created() []string {
+	return append([]string{}, j.created...)
+}
+
+// MkdirAll creates path and its missing parents, recording each directory
+// it creates
+func (j *Journal) MkdirAll(path string) error {
+	var missing []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		missing = append(missing, dir)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := os.Mkdir(missing[i], 0755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		j.record(missing[i])
+	}
+	return nil
+}
+
+// WriteFile writes a file, recording it when new and keeping a backup of
+// the original content when it is overwritten
+func (j *Journal) WriteFile(path string, data []byte, mode fs.FileMode) error {
+	_, err := j.WriteFileFrom(path, bytes.NewReader(data), mode)
+	return err
+}
+
+// WriteFileFrom is WriteFile streaming the content from r, so it is never
+// held in memory. It returns the number of bytes written.
+func (j *Journal) WriteFileFrom(path string, r io.Reader, mode fs.FileMode) (int64, error) {
+	if err := j.MkdirAll(filepath.Dir(path)); err != nil {
+		return 0, err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		j.backups = append(j.backups, backup{path: path, data: original, mode: info.Mode().Perm()})
+	} else {
+		j.record(path)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+// Symlink creates a symbolic link at path pointing to target, recording it
+func (j *Journal) Symlink(target, path string) error {
+	if err := j.MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := os.Symlink(target, path); err != nil {
+		return err
+	}
+	j.record(path)
+	return nil
+}
+
+// RecordNew records the entries of root that appeared since they were
+// last seen, e.g. node_modules after running a package manager
+func (j *Journal) RecordNew() error {
+	entries, err := os.ReadDir(j.root)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !j.known[entry.Name()] {
+			j.record(filepath.Join(j.root, entry.Name()))
+		}
+	}
+	return nil
+}
+
+// Rollback removes everything the journal recorded, newest first, and
+// restores overwritten files
+func (j *Journal) Rollback() error {
+	var errs []error
+	if j.rootCreated {
+		if err := os.RemoveAll(j.root); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i := len(j.created) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(j.created[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := len(j.backups) - 1; i >= 0; i-- {
+		b := j.backups[i]
+		if err := os.WriteFile(b.path, b.data, b.mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	j.created = nil
+	j.backups = nil
+	return errors.Join(errs...)
+}
+
+// record adds a created path, marking top-level entries of root as known
+func (j *Journal) record(path string) {
+	if path == j.root {
+		return
+	}
+	if filepath.Dir(path) == j.root {
+		if j.known[filepath.Base(path)] {
+			return
+		}
+		j.known[filepath.Base(path)] = true
+	}
+	j.created = append(j.created, path)
+}
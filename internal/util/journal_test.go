@@ -0,0 +1,206 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile creates a file with its parent directories
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertContent fails unless path holds content
+func assertContent(t *testing.T, path, content string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, content)
+	}
+}
+
+// assertMissing fails when path exists
+func assertMissing(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed: %v", path, err)
+	}
+}
+
+func TestJournalRollbackKeepsExistingFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "README.md"), "# mine")
+	writeTestFile(t, filepath.Join(root, ".gitignore"), "secrets\n")
+	writeTestFile(t, filepath.Join(root, "LICENSE"), "MIT")
+	writeTestFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"README.md", ".gitignore", "app/page.tsx"} {
+		if err := journal.WriteFile(filepath.Join(root, name), []byte("generated"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(root, ".git", "index"), "")
+	writeTestFile(t, filepath.Join(root, "node_modules", "next", "package.json"), "{}")
+	if err := journal.RecordNew(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertContent(t, filepath.Join(root, "README.md"), "# mine")
+	assertContent(t, filepath.Join(root, ".gitignore"), "secrets\n")
+	assertContent(t, filepath.Join(root, "LICENSE"), "MIT")
+	assertContent(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+	assertMissing(t, filepath.Join(root, "app"))
+	assertMissing(t, filepath.Join(root, "node_modules"))
+}
+
+func TestJournalWriteFileFrom(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "README.md"), "# mine")
+
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"README.md": "# generated", "app/page.tsx": "export default 1"} {
+		n, err := journal.WriteFileFrom(filepath.Join(root, name), strings.NewReader(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(content)) {
+			t.Errorf("WriteFileFrom(%s) = %d, want %d", name, n, len(content))
+		}
+		assertContent(t, filepath.Join(root, name), content)
+	}
+
+	if err := journal.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, filepath.Join(root, "README.md"), "# mine")
+	assertMissing(t, filepath.Join(root, "app"))
+}
+
+func TestJournalRollbackRemovesCreatedRoot(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "parent", "nested", "my-app")
+
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// git init and the package manager add entries the journal didn't write
+	writeTestFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(root, "node_modules", "next", "package.json"), "{}")
+	if err := journal.RecordNew(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertMissing(t, filepath.Join(base, "parent"))
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d entries left behind", len(entries))
+	}
+}
+
+func TestJournalRecordNew(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, "node_modules", ".package-lock.json"), "{}")
+	writeTestFile(t, filepath.Join(root, ".git", "index"), "")
+	if err := journal.RecordNew(); err != nil {
+		t.Fatal(err)
+	}
+	// Recording twice doesn't add the same entry again
+	if err := journal.RecordNew(); err != nil {
+		t.Fatal(err)
+	}
+
+	created := journal.Created()
+	if len(created) != 1 || created[0] != filepath.Join(root, "node_modules") {
+		t.Errorf("Created() = %v, want only node_modules", created)
+	}
+}
+
+func TestJournalRollbackAfterPartialMkdirAll(t *testing.T) {
+	root := t.TempDir()
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second directory has a name too long for the file system
+	tooLong := filepath.Join(root, "app", strings.Repeat("x", 300))
+	if err := journal.MkdirAll(filepath.Join(tooLong, "nested")); err == nil {
+		t.Fatal("MkdirAll() succeeded with a name longer than the file system allows")
+	}
+	if _, err := os.Stat(filepath.Join(root, "app")); err != nil {
+		t.Fatalf("MkdirAll() didn't create the first directory: %v", err)
+	}
+	if err := journal.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d entries left behind", len(entries))
+	}
+}
+
+func TestJournalRollbackRemovesRecordedEntries(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "LICENSE"), "MIT")
+
+	journal, err := NewJournal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(root, "node_modules", "next", "package.json"), "{}")
+	if err := journal.RecordNew(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertMissing(t, filepath.Join(root, ".git"))
+	assertMissing(t, filepath.Join(root, "node_modules"))
+	assertContent(t, filepath.Join(root, "LICENSE"), "MIT")
+}
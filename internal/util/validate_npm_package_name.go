@@ -163,6 +163,11 @@ func ValidateNpmPackageName(name string) ValidationResult {
 	return result
 }
 
+// IsBuiltinModule reports whether name is a Node.js core module or reserved name
+func IsBuiltinModule(name string) bool {
+	return builtinModules[strings.ToLower(name)]
+}
+
 // IsValidPackageName is a convenience function that returns true if the name is valid
 func IsValidPackageName(name string) bool {
 	result := ValidateNpmPackageName(name)
@@ -1,12 +1,40 @@
 package validate
 
 import (
-	"net"
+	"context"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
+// DefaultProbeTimeout bounds a connectivity probe
+const DefaultProbeTimeout = 3 * time.Second
+
+// ProbeOptions configure a connectivity probe
+type ProbeOptions struct {
+	Registry string        // Registry URL; detected from the npm config when empty
+	Dir      string        // Project directory whose .npmrc is considered
+	Timeout  time.Duration // Defaults to DefaultProbeTimeout
+	Client   *http.Client  // Defaults to a client honouring HTTPS_PROXY and NO_PROXY
+}
+
+// onlineCache holds probe results per registry for the rest of the run
+var onlineCache = struct {
+	sync.Mutex
+	results map[string]bool
+}{results: map[string]bool{}}
+
+// defaultClient sends probes through the proxy from the environment
+var defaultClient = &http.Client{
+	Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	// A redirect is an answer too, don't follow it
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
 // IsCI detects if running in a CI environment
 func IsCI() bool {
 	ciEnvVars := []string{
@@ -31,13 +59,59 @@ func IsCI() bool {
 	return false
 }
 
-// IsOnline checks network connectivity
+// IsOnline checks connectivity to the npm registry of the current directory
 func IsOnline() bool {
-	// Try to resolve a well-known domain
-	conn, err := net.DialTimeout("tcp", "registry.npmjs.org:443", 3*time.Second)
+	return CheckOnline(context.Background(), ProbeOptions{})
+}
+
+// CheckOnline reports whether the effective npm registry answers HTTP
+// requests. Any response counts, since private registries commonly reject
+// anonymous requests. The result is cached per registry for the run,
+// unless ctx ended before the probe could answer.
+func CheckOnline(ctx context.Context, opts ProbeOptions) bool {
+	registry := opts.Registry
+	if registry == "" {
+		registry = Registry(opts.Dir)
+	}
+
+	onlineCache.Lock()
+	defer onlineCache.Unlock()
+	if online, ok := onlineCache.results[registry]; ok {
+		return online
+	}
+
+	online := probe(ctx, registry, opts)
+	if ctx.Err() == nil {
+		onlineCache.results[registry] = online
+	}
+	return online
+}
+
+// probe sends a single request to the registry
+func probe(ctx context.Context, registry string, opts ProbeOptions) bool {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	client := opts.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, registry, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return false
 	}
-	conn.Close()
-	return true
+	resp.Body.Close()
+
+	// A proxy answers these when it cannot reach the registry
+	return resp.StatusCode != http.StatusBadGateway && resp.StatusCode != http.StatusGatewayTimeout
 }
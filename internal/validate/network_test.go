@@ -0,0 +1,261 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// registryServer starts a stand-in registry answering with status and
+// counting the requests it receives
+func registryServer(t *testing.T, status int, delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestCheckOnline(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		delay  time.Duration
+		want   bool
+	}{
+		{"reachable", http.StatusOK, 0, true},
+		{"private registry", http.StatusUnauthorized, 0, true},
+		{"bad gateway", http.StatusBadGateway, 0, false},
+		{"gateway timeout", http.StatusGatewayTimeout, 0, false},
+		{"timeout", http.StatusOK, time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := registryServer(t, tt.status, tt.delay)
+			opts := ProbeOptions{Registry: server.URL, Timeout: 100 * time.Millisecond}
+
+			if got := CheckOnline(context.Background(), opts); got != tt.want {
+				t.Errorf("CheckOnline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOnlineCachesResults(t *testing.T) {
+	server, requests := registryServer(t, http.StatusOK, 0)
+	opts := ProbeOptions{Registry: server.URL}
+
+	for range 3 {
+		if !CheckOnline(context.Background(), opts) {
+			t.Fatal("CheckOnline() = false, want true")
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("the registry was probed %d times, want 1", got)
+	}
+}
+
+func TestCheckOnlineCancelledIsNotCached(t *testing.T) {
+	server, requests := registryServer(t, http.StatusOK, 0)
+	opts := ProbeOptions{Registry: server.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if CheckOnline(ctx, opts) {
+		t.Fatal("CheckOnline() with a cancelled context = true, want false")
+	}
+	if !CheckOnline(context.Background(), opts) {
+		t.Error("CheckOnline() = false after a cancelled probe, want true")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("the registry was probed %d times, want 1", got)
+	}
+}
+
+// isolateNpmrc points the user and global npmrc at missing files and
+// clears the registry environment
+func isolateNpmrc(t *testing.T) {
+	t.Helper()
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("npm_config_userconfig", missing)
+	t.Setenv("npm_config_globalconfig", missing)
+	for _, name := range envRegistryVars {
+		t.Setenv(name, "")
+	}
+}
+
+// writeNpmrc writes a project .npmrc and returns its directory
+func writeNpmrc(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".npmrc"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		isolateNpmrc(t)
+		if got := Registry(t.TempDir()); got != DefaultRegistry {
+			t.Errorf("Registry() = %q, want %q", got, DefaultRegistry)
+		}
+	})
+
+	t.Run("project npmrc", func(t *testing.T) {
+		isolateNpmrc(t)
+		dir := writeNpmrc(t, "# comment\nregistry = \"https://npm.example.com/\"\n")
+		if got := Registry(dir); got != "https://npm.example.com/" {
+			t.Errorf("Registry() = %q", got)
+		}
+	})
+
+	t.Run("environment overrides project npmrc", func(t *testing.T) {
+		isolateNpmrc(t)
+		t.Setenv("npm_config_registry", "https://env.example.com/")
+		dir := writeNpmrc(t, "registry=https://npm.example.com/\n")
+		if got := Registry(dir); got != "https://env.example.com/" {
+			t.Errorf("Registry() = %q", got)
+		}
+	})
+
+	t.Run("expands variables", func(t *testing.T) {
+		isolateNpmrc(t)
+		t.Setenv("REGISTRY_HOST", "npm.internal")
+		dir := writeNpmrc(t, "registry=https://${REGISTRY_HOST}/npm/\n")
+		if got := Registry(dir); got != "https://npm.internal/npm/" {
+			t.Errorf("Registry() = %q", got)
+		}
+	})
+
+	t.Run("user npmrc", func(t *testing.T) {
+		isolateNpmrc(t)
+		user := filepath.Join(writeNpmrc(t, "registry=https://user.example.com/\n"), ".npmrc")
+		t.Setenv("npm_config_userconfig", user)
+		if got := Registry(t.TempDir()); got != "https://user.example.com/" {
+			t.Errorf("Registry() = %q", got)
+		}
+	})
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("NPM_HOST", "npm.internal")
+	t.Setenv("EMPTY", "")
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"https://${NPM_HOST}/", "https://npm.internal/"},
+		{"${NPM_HOST}${NPM_HOST}", "npm.internalnpm.internal"},
+		{"pa$$word$NPM_HOST", "pa$$word$NPM_HOST"},
+		{"${UNSET_VARIABLE}", "${UNSET_VARIABLE}"},
+		{"x${UNSET_VARIABLE?}y", "xy"},
+		{"${EMPTY}", ""},
+		{`\${NPM_HOST}`, "${NPM_HOST}"},
+		{`\\${NPM_HOST}`, `\npm.internal`},
+		{"$NPM_HOST${", "$NPM_HOST${"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := expandEnv(tt.value); got != tt.want {
+				t.Errorf("expandEnv(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// proxyHelperRegistry is set for the test binary re-run by runProbe
+const proxyHelperRegistry = "PROBE_HELPER_REGISTRY"
+
+// TestProbeHelper probes the registry for runProbe. It runs in its own
+// process since net/http reads the proxy environment only once.
+func TestProbeHelper(t *testing.T) {
+	registry := os.Getenv(proxyHelperRegistry)
+	if registry == "" {
+		t.Skip("run by TestCheckOnlineProxy")
+	}
+	fmt.Printf("online=%v\n", CheckOnline(context.Background(), ProbeOptions{Registry: registry, Timeout: 2 * time.Second}))
+}
+
+// runProbe probes registry in a new process with the proxy environment
+// env and returns whether it was online
+func runProbe(t *testing.T, registry string, env ...string) bool {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProbeHelper$", "-test.v")
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		switch strings.ToUpper(name) {
+		case "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "REQUEST_METHOD":
+			continue
+		}
+		cmd.Env = append(cmd.Env, kv)
+	}
+	cmd.Env = append(cmd.Env, append(env, proxyHelperRegistry+"="+registry)...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	return strings.Contains(string(out), "online=true")
+}
+
+func TestCheckOnlineProxy(t *testing.T) {
+	var requests atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method == http.MethodConnect {
+			// Tunnels are refused, the registry is unreachable through it
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	// The registry only exists behind the proxy
+	const httpRegistry = "http://registry.invalid/"
+	const httpsRegistry = "https://registry.invalid/"
+
+	tests := []struct {
+		name     string
+		registry string
+		env      []string
+		want     bool
+		proxied  bool
+	}{
+		{"HTTP_PROXY", httpRegistry, []string{"HTTP_PROXY=" + proxy.URL}, true, true},
+		{"HTTPS_PROXY", httpsRegistry, []string{"HTTPS_PROXY=" + proxy.URL}, false, true},
+		{"NO_PROXY", httpRegistry, []string{"HTTP_PROXY=" + proxy.URL, "NO_PROXY=.invalid"}, false, false},
+		{"no proxy", httpRegistry, nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			if got := runProbe(t, tt.registry, tt.env...); got != tt.want {
+				t.Errorf("CheckOnline() = %v, want %v", got, tt.want)
+			}
+			if proxied := requests.Load() > 0; proxied != tt.proxied {
+				t.Errorf("went through the proxy = %v, want %v", proxied, tt.proxied)
+			}
+		})
+	}
+}
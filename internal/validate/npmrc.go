@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// DefaultRegistry is the public npm registry
+const DefaultRegistry = "https://registry.npmjs.org/"
+
+// envRegistryVars are the environment variables npm reads the registry from
+var envRegistryVars = []string{"npm_config_registry", "NPM_CONFIG_REGISTRY"}
+
+// Registry returns the npm registry in effect for a project in dir, using
+// npm's precedence: environment, project .npmrc, user .npmrc, global npmrc
+func Registry(dir string) string {
+	for _, name := range envRegistryVars {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+
+	for _, path := range npmrcPaths(dir) {
+		if value := readNpmrc(path)["registry"]; value != "" {
+			return value
+		}
+	}
+
+	return DefaultRegistry
+}
+
+// npmrcPaths returns the npmrc files for dir from highest to lowest priority
+func npmrcPaths(dir string) []string {
+	var paths []string
+
+	if dir != "" {
+		paths = append(paths, filepath.Join(dir, ".npmrc"))
+	}
+
+	if userConfig := os.Getenv("npm_config_userconfig"); userConfig != "" {
+		paths = append(paths, userConfig)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".npmrc"))
+	}
+
+	if globalConfig := os.Getenv("npm_config_globalconfig"); globalConfig != "" {
+		paths = append(paths, globalConfig)
+	} else if prefix := npmPrefix(); prefix != "" {
+		paths = append(paths, filepath.Join(prefix, "etc", "npmrc"))
+	}
+
+	return paths
+}
+
+// npmPrefix returns npm's global prefix, derived from the node executable
+// like npm does when npm_config_prefix is unset
+func npmPrefix() string {
+	if prefix := os.Getenv("npm_config_prefix"); prefix != "" {
+		return prefix
+	}
+
+	node, err := exec.LookPath("node")
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Dir(node)
+	}
+	return filepath.Dir(filepath.Dir(node))
+}
+
+// readNpmrc parses the key=value pairs of an npmrc file, expanding
+// ${VAR} references. A missing file yields no values.
+func readNpmrc(path string) map[string]string {
+	values := map[string]string{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		values[strings.TrimSpace(key)] = expandEnv(value)
+	}
+
+	return values
+}
+
+// envReference matches a ${VAR} or ${VAR?} reference and the backslashes
+// before it
+var envReference = regexp.MustCompile(`(\\*)\$\{([^${}?]+)(\?)?\}`)
+
+// expandEnv replaces ${VAR} references like npm does. A bare $VAR is kept,
+// an unset variable is kept as ${VAR}, or removed with ${VAR?}, and
+// backslashes escape the reference.
+func expandEnv(value string) string {
+	return envReference.ReplaceAllStringFunc(value, func(match string) string {
+		parts := envReference.FindStringSubmatch(match)
+		escapes, name, optional := parts[1], parts[2], parts[3]
+		if len(escapes)%2 == 1 {
+			return match[(len(escapes)+1)/2:]
+		}
+
+		env, ok := os.LookupEnv(name)
+		if !ok && optional == "" {
+			env = "${" + name + "}"
+		}
+		return escapes[len(escapes)/2:] + env
+	})
+}
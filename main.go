@@ -5,7 +5,9 @@ Author:  Md Kawsar Islam Yeasin <mdkawsarislam2002@gmail.com>
 package main
 
 import (
+	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -14,18 +16,28 @@ import (
 	"github.com/yeasin2002/better-next-app/cmd"
 )
 
-//go:embed templates
+//go:embed all:templates
 var templatesFS embed.FS
 
 func main() {
-	// handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// handle graceful shutdown: the first signal cancels running work, a
+	// second one exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	err := cmd.Execute(templatesFS)
+	err := cmd.Execute(ctx, templatesFS)
 	if err != nil {
-		fmt.Println("Something Went Wrong!!")
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Something Went Wrong!!")
+
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		os.Exit(cmd.ExitFailure)
 	}
 
 }